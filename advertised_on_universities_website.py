@@ -80,7 +80,8 @@ def filter_and_send_emails(uni):
                 and not forbidden_words_search_result
             ):
 
-                id = f"{customer.email}_{uni.db_name}_{position.id}"
+                table_name, position_id = position.tracking_key()
+                id = f"{customer.email}_{table_name}_{position_id}"
                 if not tracking_emails_db.check_if_id_exist(
                     "keep_track_of_sent_emails", id
                 ):
@@ -93,7 +94,7 @@ def filter_and_send_emails(uni):
                                 position.date,
                             ),
                             TrackingEmails(
-                                id, uni.db_name, customer.email, position.id
+                                id, table_name, customer.email, position_id
                             ),
                         ]
                     )
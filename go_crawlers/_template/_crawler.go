@@ -0,0 +1,53 @@
+package __UNI_NAME__
+
+import (
+	"context"
+
+	"fenjan.ai-hue.ir/tea"
+	"github.com/gocolly/colly"
+)
+
+// Set the university name, the database table name for this university, and the url of vacant positions
+var uniName string = ""
+var tableName string = ""
+var vacantPositionsUrl string = ""
+
+// Get Position and Listing types from tea helper package
+type Position = tea.Position
+type Listing = tea.Listing
+
+// source implements tea.Source for the university website
+type source struct{}
+
+func (source) Name() string      { return uniName }
+func (source) TableName() string { return tableName }
+
+// get the URL of all vacant positions
+func (source) List(ctx context.Context) (listings []Listing, err error) {
+
+	c := tea.NewCollector(ctx)
+
+	// 🧟‍♀️------------------------------------------------------------------------------
+
+	err = c.Visit(vacantPositionsUrl)
+
+	return listings, err
+}
+
+// Get the details of position
+func (source) Detail(ctx context.Context, listing Listing) (Position, error) {
+	position := listing.Position()
+
+	c := tea.NewCollector(ctx)
+
+	// 🧟‍♀️------------------------------------------------------------------------------
+
+	err := c.Visit(position.URL)
+
+	return position, err
+}
+
+func init() {
+	// The city, country and language (ISO 639-1 code) of the positions, leave out the ones that differ between them
+	tea.Register(source{}, tea.Location{City: "", Country: "", Language: ""})
+}
@@ -1,7 +0,0 @@
-module fenjan.ai-hue.ir/__UNI_NAME__
-
-replace fenjan.ai-hue.ir/tea => ../utils/tea
-
-replace fenjan.ai-hue.ir/logger => ../utils/logger
-
-go 1.18
@@ -0,0 +1,70 @@
+package aalto_university
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"fenjan.ai-hue.ir/tea"
+	"github.com/gocolly/colly"
+)
+
+// Set the university name, the database table name for this university, and the url of vacant positions
+var uniName string = "Aalto University"
+var tableName string = "aalto_fi"
+var vacantPositionsUrl string = "https://www.aalto.fi/en/open-positions?sort_by=field_application_end_value&field_unit_target_id=All&field_category_target_id%5B13336%5D=13336&page"
+
+// Get Position and Listing types from tea helper package
+type Position = tea.Position
+type Listing = tea.Listing
+
+// source implements tea.Source for the university website
+type source struct{}
+
+func (source) Name() string      { return uniName }
+func (source) TableName() string { return tableName }
+
+// get the URL of all vacant positions
+func (source) List(ctx context.Context) (listings []Listing, err error) {
+
+	c := tea.NewCollector(ctx)
+
+	c.OnHTML("a.aalto-listing__link", func(e *colly.HTMLElement) {
+		listings = append(listings, Listing{URL: e.Request.AbsoluteURL(e.Attr("href"))})
+	})
+
+	err = c.Visit(vacantPositionsUrl)
+
+	return listings, err
+}
+
+// Get the details of position
+func (source) Detail(ctx context.Context, listing Listing) (Position, error) {
+	position := listing.Position()
+
+	c := tea.NewCollector(ctx)
+
+	c.OnHTML("div.article-container.aalto-article__top", func(e *colly.HTMLElement) {
+		position.Title = strings.TrimSpace(e.ChildText("h1"))
+
+	})
+
+	c.OnHTML("div.aalto-article__info-text time", func(e *colly.HTMLElement) {
+		position.Date = strings.TrimSpace(e.Text)
+	})
+
+	c.OnHTML("div.aalto-article div.aalto-user-generated-content", func(e *colly.HTMLElement) {
+
+		position.Description += fmt.Sprintln(strings.TrimSpace(strings.ToValidUTF8(e.Text, "")))
+
+	})
+
+	err := c.Visit(position.URL)
+
+	return position, err
+
+}
+
+func init() {
+	tea.Register(source{}, tea.Location{City: "Espoo", Country: "Finland", Language: "en"})
+}
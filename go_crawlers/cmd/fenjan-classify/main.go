@@ -0,0 +1,174 @@
+// fenjan-classify trains and evaluates the models of the classify package, which label the positions
+// as phd, postdoc, faculty, staff or other
+//
+// Usage:
+//
+//	fenjan-classify train --data examples.tsv --out model.json
+//	fenjan-classify eval --data examples.tsv [--model model.json]
+//	fenjan-classify label [--model model.json] <title> [description]
+//
+// The examples are lines of label <TAB> title <TAB> description, like utils/tea/classify/training.tsv.
+// Without --model the default model trained from training.tsv is used.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"text/tabwriter"
+
+	"fenjan.ai-hue.ir/tea/classify"
+)
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "Usage:")
+	fmt.Fprintln(os.Stderr, "  fenjan-classify train --data examples.tsv --out model.json   train a model from the labeled examples")
+	fmt.Fprintln(os.Stderr, "  fenjan-classify eval --data examples.tsv [--model ...]       show how well a model labels the examples")
+	fmt.Fprintln(os.Stderr, "  fenjan-classify label [--model ...] <title> [description]    label a position")
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+
+	switch os.Args[1] {
+	case "train":
+		train(os.Args[2:])
+	case "eval":
+		eval(os.Args[2:])
+	case "label":
+		label(os.Args[2:])
+	default:
+		usage()
+		os.Exit(2)
+	}
+}
+
+// train trains a model from the examples and saves it
+func train(args []string) {
+	flags := flag.NewFlagSet("train", flag.ExitOnError)
+	data := flags.String("data", "", "TSV file of the labeled examples")
+	out := flags.String("out", "model.json", "file the model is saved to")
+	flags.Usage = usage
+	flags.Parse(args)
+
+	examples := readExamples(*data)
+	file, err := os.Create(*out)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer file.Close()
+	if err := classify.Train(examples).Save(file); err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("Trained the model from %d examples and saved it to %s 🧠.", len(examples), *out)
+}
+
+// eval labels the examples and prints the precision and recall of each label
+func eval(args []string) {
+	flags := flag.NewFlagSet("eval", flag.ExitOnError)
+	data := flags.String("data", "", "TSV file of the labeled examples")
+	model := flags.String("model", "", "model saved by train, the default model if empty")
+	flags.Usage = usage
+	flags.Parse(args)
+
+	classifier := classifierOf(*model)
+	var results []classify.Result
+	examples := readExamples(*data)
+	for _, example := range examples {
+		results = append(results, classifier.Classify(example.Title, example.Description))
+	}
+	printEvaluation(os.Stdout, examples, results)
+}
+
+// printEvaluation prints the accuracy and the precision and recall of each label
+func printEvaluation(w io.Writer, examples []classify.Example, results []classify.Result) {
+	var correct int
+	predicted, actual, truePositives := map[classify.Label]int{}, map[classify.Label]int{}, map[classify.Label]int{}
+	for i, example := range examples {
+		predicted[results[i].Label]++
+		actual[example.Label]++
+		if results[i].Label == example.Label {
+			truePositives[example.Label]++
+			correct++
+		}
+	}
+
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "label\texamples\tprecision\trecall\t")
+	for _, label := range classify.Labels {
+		fmt.Fprintf(table, "%s\t%d\t%s\t%s\t\n", label, actual[label],
+			ratio(truePositives[label], predicted[label]), ratio(truePositives[label], actual[label]))
+	}
+	table.Flush()
+	fmt.Fprintf(w, "accuracy %s of %d examples\n", ratio(correct, len(examples)), len(examples))
+}
+
+func ratio(n, total int) string {
+	if total == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%.2f", float64(n)/float64(total))
+}
+
+// label prints the label of a position and the probability of each label
+func label(args []string) {
+	flags := flag.NewFlagSet("label", flag.ExitOnError)
+	model := flags.String("model", "", "model saved by train, the default model if empty")
+	flags.Usage = usage
+	flags.Parse(args)
+	if flags.NArg() == 0 || flags.NArg() > 2 {
+		usage()
+		os.Exit(2)
+	}
+
+	classifier := classifierOf(*model)
+	title, description := flags.Arg(0), flags.Arg(1)
+	result := classifier.Classify(title, description)
+	fmt.Printf("%s (%.2f)\n", result.Label, result.Confidence)
+	scores := classifier.Scores(title, description)
+	for _, label := range classify.Labels {
+		fmt.Printf("  %-8s %.2f\n", label, scores[label])
+	}
+}
+
+// classifierOf returns the classifier of the model saved in the file, the default classifier if there is no file
+func classifierOf(path string) *classify.Classifier {
+	if path == "" {
+		return classify.Default()
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer file.Close()
+	model, err := classify.LoadModel(file)
+	if err != nil {
+		log.Fatal(err)
+	}
+	return classify.New(model)
+}
+
+// readExamples reads the labeled examples of the TSV file
+func readExamples(path string) []classify.Example {
+	if path == "" {
+		log.Fatal("--data is required")
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer file.Close()
+	examples, err := classify.ReadExamples(file)
+	if err != nil {
+		log.Fatalf("%s: %v", path, err)
+	}
+	if len(examples) == 0 {
+		log.Fatalf("%s has no examples", path)
+	}
+	return examples
+}
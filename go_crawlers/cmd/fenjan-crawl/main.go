@@ -0,0 +1,212 @@
+// fenjan-crawl runs the university crawlers registered in the tea package
+//
+// Usage:
+//
+//	fenjan-crawl list
+//	fenjan-crawl run [--timeout 30m] [--concurrency 4] [--retries 2] <table name>...
+//	fenjan-crawl run [--timeout 30m] [--concurrency 4] [--retries 2] --all
+//
+// The universities are crawled concurrently, a summary of each university is printed at the end,
+// and the exit status is 1 if crawling any university failed.
+// The logs are configured by the LOG_* environment variables or the --log-* flags of run, see the logger package.
+// The Prometheus metrics are served while crawling with --metrics-addr, or pushed after it with --pushgateway.
+// The positions are labeled by the default classifier of the classify package, or by a model trained
+// with fenjan-classify given with --classifier-model.
+// SIGINT or SIGTERM stops the crawl, the positions extracted so far are saved before exiting.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	"fenjan.ai-hue.ir/crawlers/sources"
+	"fenjan.ai-hue.ir/logger"
+	"fenjan.ai-hue.ir/tea"
+	"fenjan.ai-hue.ir/tea/classify"
+	"fenjan.ai-hue.ir/tea/metrics"
+)
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "Usage:")
+	fmt.Fprintln(os.Stderr, "  fenjan-crawl list                   list the registered universities")
+	fmt.Fprintln(os.Stderr, "  fenjan-crawl run <table name>...     crawl the given universities")
+	fmt.Fprintln(os.Stderr, "  fenjan-crawl run --all               crawl all the registered universities")
+	fmt.Fprintln(os.Stderr, "  fenjan-crawl run --timeout 30m ...   stop crawling a university after the timeout")
+	fmt.Fprintln(os.Stderr, "  fenjan-crawl run --concurrency 4 ... crawl at most 4 universities at the same time")
+	fmt.Fprintln(os.Stderr, "  fenjan-crawl run --retries 2 ...     try crawling a failed university again 2 times")
+	fmt.Fprintln(os.Stderr, "  fenjan-crawl run --log-format json   log JSON records instead of text, also --log-level and --log-file")
+	fmt.Fprintln(os.Stderr, "  fenjan-crawl run --metrics-addr ...  serve the Prometheus metrics at /metrics while crawling, e.g. on :9090")
+	fmt.Fprintln(os.Stderr, "  fenjan-crawl run --pushgateway ...   push the metrics to the Pushgateway at the URL after crawling")
+	fmt.Fprintln(os.Stderr, "  fenjan-crawl run --alert-webhook ... post the source broken alerts to the URL")
+	fmt.Fprintln(os.Stderr, "  fenjan-crawl run --classifier-model ... label the positions with the model saved by fenjan-classify train")
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+
+	if err := sources.LoadDefinitionsDir(); err != nil {
+		log.Fatal(err)
+	}
+
+	switch os.Args[1] {
+	case "list":
+		listSources()
+	case "run":
+		runSources(os.Args[2:])
+	default:
+		usage()
+		os.Exit(2)
+	}
+}
+
+// listSources prints the table name and the name of all registered universities
+func listSources() {
+	for _, source := range tea.Sources() {
+		fmt.Printf("%-24s %s\n", source.TableName(), source.Name())
+	}
+}
+
+// runSources parses the arguments of the run command, crawls the selected universities concurrently
+// and prints a summary of them
+func runSources(args []string) {
+	flags := flag.NewFlagSet("run", flag.ExitOnError)
+	all := flags.Bool("all", false, "crawl all the registered universities")
+	timeout := flags.Duration("timeout", 30*time.Minute, "maximum duration of crawling one university")
+	concurrency := flags.Int("concurrency", 4, "number of universities crawled at the same time")
+	retries := flags.Int("retries", 2, "number of times a failed university is crawled again")
+	retryWait := flags.Duration("retry-wait", 30*time.Second, "wait before crawling a failed university again, it grows with each retry")
+	metricsAddr := flags.String("metrics-addr", os.Getenv("METRICS_ADDR"), "address to serve the Prometheus metrics on at /metrics while crawling, e.g. :9090")
+	pushgateway := flags.String("pushgateway", os.Getenv("PUSHGATEWAY_URL"), "URL of a Pushgateway to push the metrics to after crawling")
+	alertWebhook := flags.String("alert-webhook", os.Getenv("ALERT_WEBHOOK_URL"), "URL the source broken alerts are posted to as JSON, they are logged in any case")
+	classifierModel := flags.String("classifier-model", os.Getenv("CLASSIFIER_MODEL"), "model saved by fenjan-classify train that labels the positions, the default model if empty")
+	logConfig := logger.ConfigFromEnv()
+	logConfig.RegisterFlags(flags)
+	flags.Usage = usage
+	flags.Parse(args)
+
+	if err := logger.Setup(logConfig); err != nil {
+		log.Fatal(err)
+	}
+
+	var sources []tea.Source
+	if *all {
+		sources = tea.Sources()
+	} else {
+		for _, name := range flags.Args() {
+			source, ok := tea.LookupSource(name)
+			if !ok {
+				log.Fatalf("Unknown university %q, use 'fenjan-crawl list' to see the available ones", name)
+			}
+			sources = append(sources, source)
+		}
+	}
+	if len(sources) == 0 {
+		usage()
+		os.Exit(2)
+	}
+
+	if *classifierModel != "" {
+		model, err := loadModel(*classifierModel)
+		if err != nil {
+			log.Fatal(err)
+		}
+		tea.Classifier = classify.New(model)
+	}
+
+	// Connecting to the database
+	log.Println("Connecting to the 'fenjan' database 🐰.")
+	store, err := tea.OpenStore()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer store.Close()
+
+	// Stop crawling on SIGINT or SIGTERM
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Send the source broken alerts of the health check to the webhook too
+	if *alertWebhook != "" {
+		tea.HealthNotifier = tea.Notifiers{tea.LogNotifier{}, &tea.WebhookNotifier{URL: *alertWebhook}}
+	}
+
+	// Serve the metrics while crawling
+	if *metricsAddr != "" {
+		go func() {
+			if err := metrics.Serve(ctx, *metricsAddr); err != nil {
+				slog.Error("Serving the metrics failed ☠️!", logger.ErrorKey, err)
+			}
+		}()
+	}
+
+	reports := tea.Run(ctx, store, sources, tea.RunOptions{
+		Concurrency: *concurrency,
+		Retries:     *retries,
+		RetryWait:   *retryWait,
+		Timeout:     *timeout,
+	})
+
+	failed := false
+	for _, report := range reports {
+		if report.Err != nil {
+			failed = true
+			logger.Failure(logger.ForSource(report.Source.TableName()), tea.ErrorURL(report.Err), tea.ErrorKind(report.Err), report.Err)
+		}
+	}
+	printSummary(os.Stdout, reports)
+
+	// Push the metrics, the run ends before Prometheus can scrape them
+	if *pushgateway != "" {
+		if err := metrics.Push(*pushgateway, "fenjan_crawl"); err != nil {
+			slog.Error("Pushing the metrics failed ☠️!", logger.ErrorKey, err)
+		}
+	}
+
+	if failed {
+		store.Close()
+		os.Exit(1)
+	}
+}
+
+// loadModel reads the classifier model saved in the file
+func loadModel(path string) (*classify.Model, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return classify.LoadModel(file)
+}
+
+// printSummary prints a table of the outcome of crawling each university
+func printSummary(w io.Writer, reports []tea.RunReport) {
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "source\tstatus\tlisted\tnew\tupdated\trejected\terrors\tduration\t")
+	for _, report := range reports {
+		status := "ok"
+		switch {
+		case report.Attempts == 0:
+			status = "skipped"
+		case report.Err != nil:
+			status = "failed: " + tea.ErrorKind(report.Err)
+		case report.Attempts > 1:
+			status = fmt.Sprintf("ok (%d attempts)", report.Attempts)
+		}
+		fmt.Fprintf(table, "%s\t%s\t%d\t%d\t%d\t%d\t%d\t%s\t\n", report.Source.TableName(), status,
+			report.Result.Listed, report.Result.New, report.Result.Changed, report.Result.Rejected, report.Result.Failed,
+			report.Duration.Round(time.Second))
+	}
+	table.Flush()
+}
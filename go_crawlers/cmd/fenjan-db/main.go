@@ -0,0 +1,179 @@
+// fenjan-db manages the database of the crawlers
+//
+// Usage:
+//
+//	fenjan-db migrate up                    apply all migrations that are not applied yet
+//	fenjan-db migrate down [steps]          revert the last applied migrations, 1 by default
+//	fenjan-db migrate status                show the migrations and whether they are applied
+//	fenjan-db merge-tables [table name...]
+//	fenjan-db tag-fields                    tag all saved positions again with the research fields of the taxonomy
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+
+	"fenjan.ai-hue.ir/crawlers/sources"
+	"fenjan.ai-hue.ir/logger"
+	"fenjan.ai-hue.ir/tea"
+	"fenjan.ai-hue.ir/tea/taxonomy"
+)
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "Usage:")
+	fmt.Fprintln(os.Stderr, "  fenjan-db migrate up                     apply all migrations that are not applied yet")
+	fmt.Fprintln(os.Stderr, "  fenjan-db migrate down [steps]           revert the last applied migrations, 1 by default")
+	fmt.Fprintln(os.Stderr, "  fenjan-db migrate status                 show the migrations and whether they are applied")
+	fmt.Fprintln(os.Stderr, "  fenjan-db merge-tables [table name...]   copy the old per university tables into the positions table")
+	fmt.Fprintln(os.Stderr, "  fenjan-db tag-fields                     tag all saved positions again with the research fields of the taxonomy")
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+
+	if err := logger.Setup(logger.ConfigFromEnv()); err != nil {
+		log.Fatal(err)
+	}
+	if err := sources.LoadDefinitionsDir(); err != nil {
+		log.Fatal(err)
+	}
+
+	switch os.Args[1] {
+	case "migrate":
+		migrate(os.Args[2:])
+	case "merge-tables":
+		mergeTables(os.Args[2:])
+	case "tag-fields":
+		tagFields()
+	default:
+		usage()
+		os.Exit(2)
+	}
+}
+
+// openSQLStore connects to the database, the commands of fenjan-db only work on SQL databases
+func openSQLStore() *tea.SQLStore {
+	log.Println("Connecting to the 'fenjan' database 🐰.")
+	store, err := tea.OpenStore()
+	if err != nil {
+		log.Fatal(err)
+	}
+	sqlStore, ok := store.(*tea.SQLStore)
+	if !ok {
+		log.Fatal("fenjan-db needs a MySQL or SQLite database, check DB_DRIVER")
+	}
+	return sqlStore
+}
+
+// migrate applies, reverts or shows the migrations of the database schema
+func migrate(args []string) {
+	if len(args) == 0 {
+		usage()
+		os.Exit(2)
+	}
+
+	store := openSQLStore()
+	defer store.Close()
+
+	switch args[0] {
+	case "up":
+		applied, err := store.MigrateUp()
+		for _, migration := range applied {
+			log.Printf("Applied migration %04d_%s 🆙.", migration.Version, migration.Name)
+		}
+		if err != nil {
+			log.Fatal("Migrating up failed ☠️! Error: ", err)
+		}
+		if len(applied) == 0 {
+			log.Println("The database is up to date 👌.")
+		}
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			var err error
+			if steps, err = strconv.Atoi(args[1]); err != nil || steps < 1 {
+				log.Fatalf("Invalid number of steps %q", args[1])
+			}
+		}
+		reverted, err := store.MigrateDown(steps)
+		for _, migration := range reverted {
+			log.Printf("Reverted migration %04d_%s ⏬.", migration.Version, migration.Name)
+		}
+		if err != nil {
+			log.Fatal("Migrating down failed ☠️! Error: ", err)
+		}
+		if len(reverted) == 0 {
+			log.Println("There is no applied migration to revert.")
+		}
+	case "status":
+		statuses, err := store.MigrationStatus()
+		if err != nil {
+			log.Fatal(err)
+		}
+		for _, status := range statuses {
+			appliedOn := "not applied"
+			if status.Applied {
+				appliedOn = "applied on " + status.AppliedOn
+			}
+			fmt.Printf("%04d %-40s %s\n", status.Version, status.Name, appliedOn)
+		}
+	default:
+		usage()
+		os.Exit(2)
+	}
+}
+
+// mergeTables copies the given per university tables, or the tables of all registered sources,
+// into the positions table
+func mergeTables(tableNames []string) {
+	if len(tableNames) == 0 {
+		for _, source := range tea.Sources() {
+			tableNames = append(tableNames, source.TableName())
+		}
+	}
+
+	store := openSQLStore()
+	defer store.Close()
+
+	log.Println("Creating the tables in the 'fenjan' database if not exists 👾.")
+	if err := store.CreateTablesIfNotExist(); err != nil {
+		log.Fatal(err)
+	}
+
+	for _, tableName := range tableNames {
+		copied, err := store.CopyLegacyTable(tableName)
+		if err != nil {
+			log.Fatalf("Copying the '%s' table failed ☠️! Error: %v", tableName, err)
+		}
+		if copied < 0 {
+			log.Printf("There is no '%s' table, skipping it.", tableName)
+			continue
+		}
+		log.Printf("Copied %d positions from the '%s' table 🚚.", copied, tableName)
+	}
+
+	log.Println("Finished 🫡!")
+}
+
+// tagFields tags the saved positions with the current taxonomy, the positions saved before the taxonomy
+// or one of its fields was added are only tagged when they are crawled again otherwise
+func tagFields() {
+	store := openSQLStore()
+	defer store.Close()
+
+	log.Println("Creating the tables in the 'fenjan' database if not exists 👾.")
+	if err := store.CreateTablesIfNotExist(); err != nil {
+		log.Fatal(err)
+	}
+
+	tagged, err := store.TagPositions(taxonomy.Default().Tag)
+	if err != nil {
+		log.Fatal("Tagging the positions failed ☠️! Error: ", err)
+	}
+	log.Printf("Tagged %d positions with their research fields 🏷️.", tagged)
+}
@@ -0,0 +1,133 @@
+// fenjan-notify emails the customers the positions saved by the crawlers that match their keywords
+//
+// Usage:
+//
+//	fenjan-notify [--dry-run] [--out emails] <table name>...
+//	fenjan-notify [--dry-run] [--out emails] --all
+//
+// Each customer gets one email per university with the open positions they haven't received yet,
+// which are then recorded in the keep_track_of_sent_emails table. The emails are sent with the SMTP
+// server of the EMAIL_ADDRESS, EMAIL_PASSWORD, SMTP_HOST and SMTP_PORT environment variables,
+// --dry-run writes them into the --out directory instead and records nothing.
+// The exit status is 1 if notifying any university failed.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"fenjan.ai-hue.ir/crawlers/match"
+	"fenjan.ai-hue.ir/crawlers/sources"
+	"fenjan.ai-hue.ir/logger"
+	"fenjan.ai-hue.ir/tea"
+)
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "Usage:")
+	fmt.Fprintln(os.Stderr, "  fenjan-notify <table name>...           email the new positions of the given universities")
+	fmt.Fprintln(os.Stderr, "  fenjan-notify --all                     email the new positions of all the registered universities")
+	fmt.Fprintln(os.Stderr, "  fenjan-notify --dry-run --out emails ... write the emails into the directory instead of sending them")
+	fmt.Fprintln(os.Stderr, "  fenjan-notify --templates dir ...       use the email templates and images of the directory")
+	fmt.Fprintln(os.Stderr, "  fenjan-notify --log-format json ...     log JSON records instead of text, also --log-level and --log-file")
+}
+
+func main() {
+	flags := flag.NewFlagSet("fenjan-notify", flag.ExitOnError)
+	all := flags.Bool("all", false, "email the new positions of all the registered universities")
+	dryRun := flags.Bool("dry-run", false, "write the emails into the --out directory instead of sending them, and don't record them as sent")
+	out := flags.String("out", "emails", "directory the emails are written into with --dry-run")
+	templates := flags.String("templates", match.DefaultTemplatesDir, "directory of email_template.html, position_template.html and the images")
+	logConfig := logger.ConfigFromEnv()
+	logConfig.RegisterFlags(flags)
+	flags.Usage = usage
+	flags.Parse(os.Args[1:])
+
+	if err := logger.Setup(logConfig); err != nil {
+		log.Fatal(err)
+	}
+	if err := sources.LoadDefinitionsDir(); err != nil {
+		log.Fatal(err)
+	}
+
+	var universities []match.University
+	if *all {
+		universities = match.Universities()
+	} else {
+		for _, name := range flags.Args() {
+			source, ok := tea.LookupSource(name)
+			if !ok {
+				log.Fatalf("Unknown university %q, use 'fenjan-crawl list' to see the available ones", name)
+			}
+			universities = append(universities, match.UniversityOf(source))
+		}
+	}
+	if len(universities) == 0 {
+		usage()
+		os.Exit(2)
+	}
+
+	composer, err := match.NewComposer(*templates)
+	if err != nil {
+		log.Fatal("Reading the email templates failed ☠️! Error: ", err)
+	}
+
+	var sender match.Sender
+	if *dryRun {
+		if err := os.MkdirAll(*out, os.ModePerm); err != nil {
+			log.Fatal(err)
+		}
+		sender = &fileSender{dir: *out}
+	} else {
+		mailer, err := match.MailerFromEnv()
+		if err != nil {
+			log.Fatal(err)
+		}
+		sender = mailer
+	}
+
+	// Connecting to the database
+	log.Println("Connecting to the 'fenjan' database 🐰.")
+	store, err := tea.OpenStore()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer store.Close()
+	sqlStore, ok := store.(*tea.SQLStore)
+	if !ok {
+		log.Fatal("fenjan-notify needs a MySQL or SQLite database, check DB_DRIVER")
+	}
+	if err := sqlStore.CreateTablesIfNotExist(); err != nil {
+		log.Fatal(err)
+	}
+
+	notifier := &match.Notifier{DB: match.NewDB(sqlStore.DB()), Composer: composer, Sender: sender, DryRun: *dryRun}
+	failed := false
+	for _, university := range universities {
+		report, err := notifier.Notify(university)
+		if err != nil {
+			failed = true
+			slog.Error("Notifying failed ☠️!", logger.SourceKey, university.TableName, logger.ErrorKey, err)
+		}
+		slog.Info(fmt.Sprintf("Sent %d positions to %d customers 📬.", report.Positions, report.Customers), logger.SourceKey, university.TableName)
+	}
+
+	if failed {
+		store.Close()
+		os.Exit(1)
+	}
+}
+
+// fileSender writes the emails into a directory, one file per recipient and subject
+type fileSender struct {
+	dir string
+}
+
+func (s *fileSender) Send(to, subject, html string) error {
+	name := strings.NewReplacer("@", "_at_", " ", "_", "/", "_").Replace(to + "_" + subject)
+	return os.WriteFile(filepath.Join(s.dir, name+".html"), []byte(html), 0644)
+}
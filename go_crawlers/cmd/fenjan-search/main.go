@@ -0,0 +1,88 @@
+// fenjan-search runs a query against the open positions saved by the crawlers, to try a subscription
+// before a customer gets emails for it
+//
+// Usage:
+//
+//	fenjan-search [--explain] <query> [table name...]
+//
+// e.g. fenjan-search '"machine learning" AND NOT title:postdoc' kth_se. The positions of all
+// registered universities are searched when no table name is given. The syntax of the queries
+// is described in the query package.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"fenjan.ai-hue.ir/crawlers/match"
+	"fenjan.ai-hue.ir/crawlers/query"
+	"fenjan.ai-hue.ir/crawlers/sources"
+	"fenjan.ai-hue.ir/tea"
+)
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "Usage:")
+	fmt.Fprintln(os.Stderr, "  fenjan-search <query>                   print the open positions of all universities matching the query")
+	fmt.Fprintln(os.Stderr, "  fenjan-search <query> <table name>...   print the open positions of the given universities matching the query")
+	fmt.Fprintln(os.Stderr, "  fenjan-search --explain <query> ...     print the parsed query with the stems of its words too")
+}
+
+func main() {
+	flags := flag.NewFlagSet("fenjan-search", flag.ExitOnError)
+	explain := flags.Bool("explain", false, "print the parsed query with the stems of its words")
+	flags.Usage = usage
+	flags.Parse(os.Args[1:])
+	if flags.NArg() == 0 {
+		usage()
+		os.Exit(2)
+	}
+
+	q, err := query.Parse(flags.Arg(0))
+	if err != nil {
+		log.Fatalf("Invalid query: %v", err)
+	}
+	if *explain {
+		fmt.Println(q)
+	}
+
+	if err := sources.LoadDefinitionsDir(); err != nil {
+		log.Fatal(err)
+	}
+	tableNames := flags.Args()[1:]
+	if len(tableNames) == 0 {
+		for _, source := range tea.Sources() {
+			tableNames = append(tableNames, source.TableName())
+		}
+	}
+
+	store, err := tea.OpenStore()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer store.Close()
+	sqlStore, ok := store.(*tea.SQLStore)
+	if !ok {
+		log.Fatal("fenjan-search needs a MySQL or SQLite database, check DB_DRIVER")
+	}
+	if err := sqlStore.CreateTablesIfNotExist(); err != nil {
+		log.Fatal(err)
+	}
+
+	db := match.NewDB(sqlStore.DB())
+	found := 0
+	for _, tableName := range tableNames {
+		positions, err := db.OpenPositions(tableName)
+		if err != nil {
+			log.Fatal(err)
+		}
+		for i := range positions {
+			if q.Match(positions[i].Document()) {
+				fmt.Printf("%-24s %s\n%-24s %s\n", tableName, positions[i].Title, "", positions[i].URL)
+				found++
+			}
+		}
+	}
+	log.Printf("Found %d positions 🔎.", found)
+}
@@ -0,0 +1,222 @@
+// Package declarative crawls the university websites that are described only by CSS selectors
+// in YAML or JSON definition files, so adding such a university doesn't need any Go code
+package declarative
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"fenjan.ai-hue.ir/tea"
+	"gopkg.in/yaml.v3"
+)
+
+// Definitions shipped with the crawler, see definitions/README.md for the format
+//
+//go:embed definitions
+var embedded embed.FS
+
+// LinkText can be used as the link attribute when the URL of a position is the text of an element
+const LinkText = "#text"
+
+// Definition describes a university website that can be crawled with CSS selectors
+type Definition struct {
+	// Name of the university, e.g. "Chalmers University of Technology"
+	Name string `yaml:"name" json:"name"`
+	// TableName is the database table of the university
+	TableName string `yaml:"table_name" json:"table_name"`
+	// City, Country and Language (ISO 639-1 code) of the positions, optional
+	City     string     `yaml:"city" json:"city"`
+	Country  string     `yaml:"country" json:"country"`
+	Language string     `yaml:"language" json:"language"`
+	List     ListPage   `yaml:"list" json:"list"`
+	Detail   DetailPage `yaml:"detail" json:"detail"`
+	// TitleRules select the PhD positions by their title when the website also has other jobs, optional
+	TitleRules tea.TitleRules `yaml:"title_rules" json:"title_rules"`
+}
+
+// ListPage describes the page that lists the vacant positions
+type ListPage struct {
+	// URL of the list page
+	URL string `yaml:"url" json:"url"`
+	// Item selects one element per position
+	Item string `yaml:"item" json:"item"`
+	// Link selects the element inside the item that has the URL of the position, empty means the item itself
+	Link string `yaml:"link" json:"link"`
+	// LinkAttribute is the attribute that has the URL of the position, "href" by default,
+	// use "#text" when the URL is the text of the element
+	LinkAttribute string `yaml:"link_attribute" json:"link_attribute"`
+	// Title selects the title of the position inside the item, optional
+	Title string `yaml:"title" json:"title"`
+	// Date selects the date of the position inside the item, optional
+	Date string `yaml:"date" json:"date"`
+	// DateIsDeadline tells that the date is the application deadline, e.g. not the publication date,
+	// so the deadline of the positions is parsed from it
+	DateIsDeadline bool `yaml:"date_is_deadline" json:"date_is_deadline"`
+	// Required are the fields of the item, "title" or "date", without which the item is skipped, optional
+	Required []string `yaml:"required" json:"required"`
+	// NextPage selects the link to the next list page, optional
+	NextPage string `yaml:"next_page" json:"next_page"`
+	// MaxPages limits the number of list pages that are visited, 10 by default
+	MaxPages int `yaml:"max_pages" json:"max_pages"`
+}
+
+// DetailPage describes the page of a single position, all selectors are optional
+type DetailPage struct {
+	// Title selects the title of the position, it replaces the title found on the list page
+	Title string `yaml:"title" json:"title"`
+	// Description selects the description of the position, the text of all matches is joined
+	Description string `yaml:"description" json:"description"`
+	// DescriptionFormat is added to the description for each match, with %s replaced by its trimmed text,
+	// "%s\n" by default
+	DescriptionFormat string `yaml:"description_format" json:"description_format"`
+	// Deadline selects the application deadline, it replaces the date found on the list page
+	Deadline string `yaml:"deadline" json:"deadline"`
+	// The other selectors select the optional details of the position with the same name
+	Department     string `yaml:"department" json:"department"`
+	EmploymentType string `yaml:"employment_type" json:"employment_type"`
+	Salary         string `yaml:"salary" json:"salary"`
+	Duration       string `yaml:"duration" json:"duration"`
+	Reference      string `yaml:"reference" json:"reference"`
+	Contact        string `yaml:"contact" json:"contact"`
+}
+
+// Location returns the location of the positions the definition is registered with
+func (d Definition) Location() tea.Location {
+	return tea.Location{City: d.City, Country: d.Country, Language: d.Language}
+}
+
+// Parse decodes a definition, format is either "yaml" or "json"
+func Parse(data []byte, format string) (Definition, error) {
+	var definition Definition
+	var err error
+	switch format {
+	case "yaml", "yml":
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+		err = decoder.Decode(&definition)
+	case "json":
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		err = decoder.Decode(&definition)
+	default:
+		return definition, fmt.Errorf("unknown definition format %q", format)
+	}
+	if err != nil {
+		return definition, err
+	}
+
+	if definition.List.LinkAttribute == "" {
+		definition.List.LinkAttribute = "href"
+	}
+	if definition.List.MaxPages == 0 {
+		definition.List.MaxPages = 10
+	}
+	if definition.Detail.DescriptionFormat == "" {
+		definition.Detail.DescriptionFormat = "%s\n"
+	}
+	return definition, definition.validate()
+}
+
+// tableNamePattern matches the table names that are safe to use in SQL and on the command line, e.g. "chalmers_se"
+var tableNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// validate checks that the fields needed to crawl the website are set
+func (d Definition) validate() error {
+	var missing []string
+	if d.Name == "" {
+		missing = append(missing, "name")
+	}
+	if d.TableName == "" {
+		missing = append(missing, "table_name")
+	}
+	if d.List.URL == "" {
+		missing = append(missing, "list.url")
+	}
+	if d.List.Item == "" {
+		missing = append(missing, "list.item")
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("definition %q is missing %s", d.Name, strings.Join(missing, ", "))
+	}
+	for _, field := range d.List.Required {
+		if (field != "title" || d.List.Title == "") && (field != "date" || d.List.Date == "") {
+			return fmt.Errorf("definition %q: the required field %q isn't selected on the list page", d.Name, field)
+		}
+	}
+	if strings.Count(d.Detail.DescriptionFormat, "%s") != 1 {
+		return fmt.Errorf("definition %q: the description format %q must have one %%s", d.Name, d.Detail.DescriptionFormat)
+	}
+	if !tableNamePattern.MatchString(d.TableName) {
+		return fmt.Errorf("definition %q: the table name %q must be lower case letters, digits and underscores", d.Name, d.TableName)
+	}
+	return nil
+}
+
+// LoadFS reads all the .yaml, .yml and .json definitions in the root of fsys
+func LoadFS(fsys fs.FS) ([]Definition, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, err
+	}
+
+	var definitions []Definition
+	for _, entry := range entries {
+		format := strings.TrimPrefix(filepath.Ext(entry.Name()), ".")
+		if entry.IsDir() || (format != "yaml" && format != "yml" && format != "json") {
+			continue
+		}
+		data, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return nil, err
+		}
+		definition, err := Parse(data, format)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", entry.Name(), err)
+		}
+		definitions = append(definitions, definition)
+	}
+	return definitions, nil
+}
+
+// RegisterDir registers the definitions found in a directory as tea sources, it fails without
+// registering any of them if one has the table name of a registered source or of another definition
+func RegisterDir(dir string) error {
+	definitions, err := LoadFS(os.DirFS(dir))
+	if err != nil {
+		return err
+	}
+	tableNames := map[string]bool{}
+	for _, definition := range definitions {
+		if _, exists := tea.LookupSource(definition.TableName); exists || tableNames[definition.TableName] {
+			return fmt.Errorf("definition %q: the table name %q is already used", definition.Name, definition.TableName)
+		}
+		tableNames[definition.TableName] = true
+	}
+	for _, definition := range definitions {
+		if err := tea.TryRegister(NewSource(definition), definition.Location()); err != nil {
+			return fmt.Errorf("definition %q: %w", definition.Name, err)
+		}
+	}
+	return nil
+}
+
+func init() {
+	fsys, err := fs.Sub(embedded, "definitions")
+	if err != nil {
+		panic(err)
+	}
+	definitions, err := LoadFS(fsys)
+	if err != nil {
+		panic(fmt.Errorf("declarative: %w", err))
+	}
+	for _, definition := range definitions {
+		tea.Register(NewSource(definition), definition.Location())
+	}
+}
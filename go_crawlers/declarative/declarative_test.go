@@ -0,0 +1,54 @@
+package declarative
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"fenjan.ai-hue.ir/tea"
+)
+
+func writeDefinitions(t *testing.T, definitions map[string]string) string {
+	dir := t.TempDir()
+	for name, definition := range definitions {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(definition), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func definitionOf(tableName string) string {
+	return "name: University " + tableName + "\ntable_name: " + tableName + "\nlist:\n  url: https://example.com\n  item: li\n"
+}
+
+func TestRegisterDirErrors(t *testing.T) {
+	tests := []struct {
+		name        string
+		definitions map[string]string
+		want        string
+	}{
+		{"registered table name", map[string]string{"a.yaml": definitionOf("chalmers_se")}, `the table name "chalmers_se" is already used`},
+		{"duplicated table name", map[string]string{"a.yaml": definitionOf("dup_edu"), "b.yaml": definitionOf("dup_edu")}, `the table name "dup_edu" is already used`},
+		{"invalid table name", map[string]string{"a.yaml": definitionOf("Bad-Name")}, "must be lower case letters, digits and underscores"},
+		{"missing table name", map[string]string{"a.yaml": "name: Nameless\nlist:\n  url: https://example.com\n  item: li\n"}, "is missing table_name"},
+	}
+	for _, test := range tests {
+		err := RegisterDir(writeDefinitions(t, test.definitions))
+		if err == nil || !strings.Contains(err.Error(), test.want) {
+			t.Errorf("%s: RegisterDir returned %v, want %q", test.name, err, test.want)
+		}
+	}
+	// A failed directory registers none of its definitions
+	if _, ok := tea.LookupSource("dup_edu"); ok {
+		t.Error("registered a definition of the failed directory")
+	}
+
+	if err := RegisterDir(writeDefinitions(t, map[string]string{"a.yaml": definitionOf("new_edu")})); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := tea.LookupSource("new_edu"); !ok {
+		t.Error("the definition wasn't registered")
+	}
+}
@@ -0,0 +1,160 @@
+package declarative
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"fenjan.ai-hue.ir/tea"
+	"github.com/gocolly/colly"
+)
+
+// Get Position and Listing types from tea helper package
+type Position = tea.Position
+type Listing = tea.Listing
+
+// source implements tea.Source for a definition
+type source struct {
+	definition Definition
+}
+
+// NewSource returns a tea.Source that crawls the website described by the definition
+func NewSource(definition Definition) tea.Source {
+	return source{definition: definition}
+}
+
+func (s source) Name() string               { return s.definition.Name }
+func (s source) TableName() string          { return s.definition.TableName }
+func (s source) TitleRules() tea.TitleRules { return s.definition.TitleRules }
+
+// The date is the application deadline when it's selected on the detail page as the deadline
+func (s source) DateIsDeadline() bool {
+	return s.definition.List.DateIsDeadline || s.definition.Detail.Deadline != ""
+}
+
+// get the URL, and the title and date when the list page has them, of all vacant positions
+func (s source) List(ctx context.Context) (listings []Listing, err error) {
+	list := s.definition.List
+
+	c := tea.NewCollector(ctx)
+
+	seen := map[string]bool{}
+	c.OnHTML(list.Item, func(e *colly.HTMLElement) {
+		url := linkOf(e, list.Link, list.LinkAttribute)
+		if url == "" || seen[url] {
+			return
+		}
+
+		listing := Listing{URL: url}
+		if list.Title != "" {
+			listing.Title = strings.TrimSpace(e.ChildText(list.Title))
+		}
+		if list.Date != "" {
+			listing.Date = strings.TrimSpace(e.ChildText(list.Date))
+		}
+		for _, field := range list.Required {
+			if (field == "title" && listing.Title == "") || (field == "date" && listing.Date == "") {
+				return
+			}
+		}
+		seen[url] = true
+		listings = append(listings, listing)
+	})
+
+	// Visit the next pages
+	var nextPageErr error
+	if list.NextPage != "" {
+		numVisitedPages := 1
+		c.OnHTML(list.NextPage, func(e *colly.HTMLElement) {
+			if numVisitedPages < list.MaxPages {
+				numVisitedPages += 1
+				// A next link back to a visited page ends the pagination
+				err := c.Visit(e.Request.AbsoluteURL(e.Attr("href")))
+				if err != nil && !errors.Is(err, colly.ErrAlreadyVisited) && nextPageErr == nil {
+					nextPageErr = err
+				}
+			}
+		})
+	}
+
+	err = c.Visit(list.URL)
+	if err == nil {
+		err = nextPageErr
+	}
+
+	return listings, err
+}
+
+// Get the details of position
+func (s source) Detail(ctx context.Context, listing Listing) (Position, error) {
+	detail := s.definition.Detail
+	position := listing.Position()
+
+	c := tea.NewCollector(ctx)
+
+	if detail.Title != "" {
+		c.OnHTML(detail.Title, func(e *colly.HTMLElement) {
+			position.Title = strings.TrimSpace(e.Text)
+		})
+	}
+
+	if detail.Description != "" {
+		c.OnHTML(detail.Description, func(e *colly.HTMLElement) {
+			text := strings.TrimSpace(strings.ToValidUTF8(e.Text, ""))
+			position.Description += strings.Replace(detail.DescriptionFormat, "%s", text, 1)
+		})
+	}
+
+	if detail.Deadline != "" {
+		c.OnHTML(detail.Deadline, func(e *colly.HTMLElement) {
+			position.Date = strings.TrimSpace(e.Text)
+		})
+	}
+
+	// The optional details of the position
+	details := []struct {
+		selector string
+		field    *string
+	}{
+		{detail.Department, &position.Department},
+		{detail.EmploymentType, &position.EmploymentType},
+		{detail.Salary, &position.Salary},
+		{detail.Duration, &position.Duration},
+		{detail.Reference, &position.Reference},
+		{detail.Contact, &position.Contact},
+	}
+	for _, d := range details {
+		if d.selector == "" {
+			continue
+		}
+		field := d.field
+		c.OnHTML(d.selector, func(e *colly.HTMLElement) {
+			*field = strings.TrimSpace(e.Text)
+		})
+	}
+
+	err := c.Visit(position.URL)
+
+	return position, err
+}
+
+// linkOf returns the absolute URL of a position from its list item
+func linkOf(e *colly.HTMLElement, selector, attribute string) string {
+	var link string
+	switch {
+	case selector == "" && attribute == LinkText:
+		link = e.Text
+	case selector == "":
+		link = e.Attr(attribute)
+	case attribute == LinkText:
+		link = e.ChildText(selector)
+	default:
+		link = e.ChildAttr(selector, attribute)
+	}
+
+	link = strings.TrimSpace(link)
+	if link == "" {
+		return ""
+	}
+	return e.Request.AbsoluteURL(link)
+}
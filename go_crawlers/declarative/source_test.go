@@ -0,0 +1,142 @@
+package declarative
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"fenjan.ai-hue.ir/tea"
+	"fenjan.ai-hue.ir/tea/fakeuni"
+)
+
+func fakeDefinition(t *testing.T, server *fakeuni.Server, maxPages int) Definition {
+	definition, err := Parse([]byte(fmt.Sprintf(`
+name: Fake University
+table_name: fake_edu
+city: Oulu
+country: Finland
+language: en
+list:
+  url: %s
+  item: li.job
+  link: a
+  next_page: a.pager__link--next
+  max_pages: %d
+detail:
+  title: h1.title
+  description: div.description p
+  deadline: span.deadline
+  department: div.department
+`, server.ListURL(), maxPages)), "yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return definition
+}
+
+func fakePositions(n int) []fakeuni.Position {
+	var positions []fakeuni.Position
+	for i := 1; i <= n; i++ {
+		positions = append(positions, fakeuni.Position{
+			ID:          fmt.Sprint(i),
+			Title:       fmt.Sprintf("Postdoctoral researcher %d", i),
+			Description: "The position is funded for two years.",
+			Department:  "Faculty of Medicine",
+			Deadline:    "2030-01-31",
+		})
+	}
+	return positions
+}
+
+func TestSourceCrawlsAllPages(t *testing.T) {
+	server := fakeuni.New(fakePositions(12)...)
+	defer server.Close()
+
+	store := tea.NewMemoryStore()
+	definition := fakeDefinition(t, server, 10)
+	source := NewSource(definition)
+	// Crawl sets the location the source is registered with
+	tea.Register(source, definition.Location())
+	result, err := tea.Crawl(context.Background(), store, source)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Listed != 12 || result.New != 12 {
+		t.Errorf("crawl: %+v, want 12 listed and new positions", result)
+	}
+
+	for _, position := range store.Positions(source.TableName()) {
+		if position.Title == "" || position.Description != "The position is funded for two years.\n" ||
+			position.Department != "Faculty of Medicine" || position.City != "Oulu" {
+			t.Errorf("unexpected position %+v", position)
+		}
+	}
+
+	// The pages after max_pages aren't visited
+	store = tea.NewMemoryStore()
+	if _, err := tea.Crawl(context.Background(), store, NewSource(fakeDefinition(t, server, 2))); err != nil {
+		t.Fatal(err)
+	}
+	if saved := store.Positions(source.TableName()); len(saved) != 10 {
+		t.Errorf("saved %d positions of 2 pages, want 10", len(saved))
+	}
+}
+
+func TestSourceRequiredFieldsAndDescriptionFormat(t *testing.T) {
+	server := fakeuni.New(
+		fakeuni.Position{ID: "1", Title: "PhD student in Physics", Description: "Four years.", Deadline: "2030-01-31"},
+		fakeuni.Position{ID: "2", Title: "Laboratory engineer", Description: "Permanent."},
+	)
+	defer server.Close()
+	definition, err := Parse([]byte(fmt.Sprintf(`
+name: Padded University
+table_name: padded_edu
+list:
+  url: %s
+  item: li.job
+  link: a
+  date: span.deadline
+  required: [date]
+detail:
+  title: h1.title
+  description: div.description p
+  description_format: "  %%s  \n"
+`, server.ListURL())), "yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	store := tea.NewMemoryStore()
+	if _, err := tea.Crawl(context.Background(), store, NewSource(definition)); err != nil {
+		t.Fatal(err)
+	}
+	// The position without a date isn't listed
+	positions := store.Positions("padded_edu")
+	if len(positions) != 1 || positions[0].Description != "  Four years.  \n" {
+		t.Errorf("saved positions %+v, want the position with a date and its padded description", positions)
+	}
+
+	// The required fields must be selected
+	if _, err := Parse([]byte("name: U\ntable_name: u\nlist:\n  url: https://example.com\n  item: li\n  required: [title]\n"), "yaml"); err == nil {
+		t.Error("parsed a definition requiring a title it doesn't select")
+	}
+}
+
+func TestSourcePaginationLoopingBack(t *testing.T) {
+	server := fakeuni.New(fakePositions(12)...)
+	defer server.Close()
+	server.LoopLastPage = true
+
+	store := tea.NewMemoryStore()
+	source := NewSource(fakeDefinition(t, server, 10))
+	result, err := tea.Crawl(context.Background(), store, source)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Listed != 12 {
+		t.Errorf("crawl: %+v, want 12 listed positions", result)
+	}
+	if n := server.Requests("/jobs"); n != 3 {
+		t.Errorf("visited %d list pages, want 3", n)
+	}
+}
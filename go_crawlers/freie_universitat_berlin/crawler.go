@@ -0,0 +1,96 @@
+package freie_universitat_berlin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"fenjan.ai-hue.ir/tea"
+	"github.com/gocolly/colly"
+	"github.com/mmcdole/gofeed"
+)
+
+// Set the university name, the database table name for this university, and the url of vacant positions
+var uniName string = "Freie Universität Berlin"
+var tableName string = "fu_berlin_de"
+var vacantPositionsUrl string = "https://www.fu-berlin.de/universitaet/beruf-karriere/jobs/english/index.rss"
+
+// The RSS feed has all jobs of the university, the PhD positions are told apart by their title
+var titleRules = tea.TitleRules{Target: []string{"PhD", "Ph.D."}}
+
+// Get Position and Listing types from tea helper package
+type Position = tea.Position
+type Listing = tea.Listing
+
+// source implements tea.Source for the university website
+type source struct{}
+
+func (source) Name() string               { return uniName }
+func (source) TableName() string          { return tableName }
+func (source) DateIsDeadline() bool       { return true }
+func (source) TitleRules() tea.TitleRules { return titleRules }
+
+// get the URL of all vacant positions
+func (source) List(ctx context.Context) (listings []Listing, err error) {
+	fp := gofeed.NewParser()
+	fp.Client = tea.HTTPClient(ctx)
+	feed, err := fp.ParseURLWithContext(vacantPositionsUrl, ctx)
+	if err != nil {
+		return nil, feedError(err)
+	}
+
+	for _, item := range feed.Items {
+		listings = append(listings, Listing{URL: item.Link, Title: strings.TrimSpace(item.Title)})
+	}
+	return listings, nil
+}
+
+// feedError returns the typed error of getting the RSS file
+func feedError(err error) error {
+	var httpErr gofeed.HTTPError
+	var urlErr *url.Error
+	switch {
+	case errors.As(err, &httpErr):
+		return &tea.StatusError{URL: vacantPositionsUrl, StatusCode: httpErr.StatusCode, Err: err}
+	case errors.As(err, &urlErr):
+		return &tea.NetworkError{URL: vacantPositionsUrl, Err: err}
+	default:
+		return &tea.ParseError{URL: vacantPositionsUrl, Err: fmt.Errorf("error in parsing the RSS file: %w", err)}
+	}
+}
+
+// Get the details of position
+func (source) Detail(ctx context.Context, listing Listing) (Position, error) {
+	position := listing.Position()
+
+	c := tea.NewCollector(ctx)
+
+	c.OnHTML("div.box-job-offer-header h2", func(e *colly.HTMLElement) {
+		position.Title = strings.TrimSpace(e.Text)
+
+	})
+
+	c.OnHTML("h3", func(e *colly.HTMLElement) {
+
+		if strings.Contains(e.Text, "Bewerbungsende") {
+
+			position.Date = strings.TrimSpace(strings.ReplaceAll(e.Text, "Bewerbungsende: ", ""))
+		}
+	})
+
+	c.OnHTML("div.editor-content p", func(e *colly.HTMLElement) {
+		position.Description += strings.TrimSpace(e.Text)
+
+	})
+
+	err := c.Visit(position.URL)
+
+	return position, err
+
+}
+
+func init() {
+	tea.Register(source{}, tea.Location{City: "Berlin", Country: "Germany", Language: "en"})
+}
@@ -0,0 +1,51 @@
+package freie_universitat_berlin
+
+import (
+	"context"
+	"html/template"
+	"testing"
+
+	"fenjan.ai-hue.ir/tea"
+	"fenjan.ai-hue.ir/tea/fakeuni"
+)
+
+// detailTemplate renders the detail pages like the website of the university
+var detailTemplate = template.Must(template.New("detail").Parse(`<!DOCTYPE html>
+<html><body>
+<div class="box-job-offer-header"><h2>{{.Title}}</h2></div>
+<h3>Bewerbungsende: {{.Deadline}}</h3>
+<div class="editor-content"><p>{{.Description}}</p></div>
+</body></html>
+`))
+
+func TestCrawlFakeServer(t *testing.T) {
+	server := fakeuni.New(
+		fakeuni.Position{ID: "1", Title: "Research Assistant (PhD candidate)", Description: "Department of Mathematics.", Deadline: "15.03.2030"},
+		fakeuni.Position{ID: "2", Title: "Postdoc in History", Description: "Friedrich Meinecke Institute.", Deadline: "01.04.2030"},
+	)
+	server.DetailTemplate = detailTemplate
+	previous := vacantPositionsUrl
+	vacantPositionsUrl = server.FeedURL()
+	defer func() {
+		vacantPositionsUrl = previous
+		server.Close()
+	}()
+	store := tea.NewMemoryStore()
+
+	result, err := tea.Crawl(context.Background(), store, source{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Listed != 2 || result.New != 1 || result.Rejected != 1 {
+		t.Errorf("crawl: %+v, want 2 listed positions, 1 new and 1 rejected by its title", result)
+	}
+	// The title of the feed is enough to reject the postdoc position
+	if n := server.Requests("/jobs/2"); n != 0 {
+		t.Errorf("visited the rejected position %d times", n)
+	}
+	for _, position := range store.Positions(tableName) {
+		if position.Title == "" || position.Description == "" || position.Deadline.IsZero() || position.City != "Berlin" {
+			t.Errorf("missing details of %+v", position)
+		}
+	}
+}
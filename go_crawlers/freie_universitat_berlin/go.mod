@@ -1,38 +0,0 @@
-module fenjan.ai-hue.ir/freie_universitat_berlin
-
-replace fenjan.ai-hue.ir/tea => ../utils/tea
-
-replace fenjan.ai-hue.ir/logger => ../utils/logger
-
-go 1.18
-
-require (
-	fenjan.ai-hue.ir/logger v0.0.0-00010101000000-000000000000
-	fenjan.ai-hue.ir/tea v0.0.0-00010101000000-000000000000
-	github.com/gocolly/colly v1.2.0
-	github.com/mmcdole/gofeed v1.1.3
-
-)
-
-require (
-	github.com/PuerkitoBio/goquery v1.8.0 // indirect
-	github.com/andybalholm/cascadia v1.3.1 // indirect
-	github.com/antchfx/htmlquery v1.2.6 // indirect
-	github.com/antchfx/xmlquery v1.3.14 // indirect
-	github.com/antchfx/xpath v1.2.2 // indirect
-	github.com/go-sql-driver/mysql v1.7.0 // indirect
-	github.com/gobwas/glob v0.2.3 // indirect
-	github.com/golang/groupcache v0.0.0-20210331224755-41bb18bfe9da // indirect
-	github.com/golang/protobuf v1.3.1 // indirect
-	github.com/joho/godotenv v1.4.0 // indirect
-	github.com/json-iterator/go v1.1.10 // indirect
-	github.com/kennygrant/sanitize v1.2.4 // indirect
-	github.com/mmcdole/goxpp v0.0.0-20181012175147-0068e33feabf // indirect
-	github.com/modern-go/concurrent v0.0.0-20180228061459-e0a39a4cb421 // indirect
-	github.com/modern-go/reflect2 v0.0.0-20180701023420-4b7aa43c6742 // indirect
-	github.com/saintfish/chardet v0.0.0-20230101081208-5e3ef4b5456d // indirect
-	github.com/temoto/robotstxt v1.1.2 // indirect
-	golang.org/x/net v0.5.0 // indirect
-	golang.org/x/text v0.6.0 // indirect
-	google.golang.org/appengine v1.6.7 // indirect
-)
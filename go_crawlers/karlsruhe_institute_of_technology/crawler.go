@@ -0,0 +1,79 @@
+package karlsruhe_institute_of_technology
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"fenjan.ai-hue.ir/tea"
+	"github.com/gocolly/colly"
+)
+
+// Set the university name, the database table name for this university, and the url of vacant positions
+var uniName string = "Karlsruhe Institute of Technology (KIT)"
+var tableName string = "kit_edu"
+var vacantPositionsUrl string = "https://www.pse.kit.edu/english/karriere/121.php"
+
+// Get Position and Listing types from tea helper package
+type Position = tea.Position
+type Listing = tea.Listing
+
+// source implements tea.Source for the university website
+type source struct{}
+
+func (source) Name() string         { return uniName }
+func (source) TableName() string    { return tableName }
+func (source) DateIsDeadline() bool { return true }
+
+// get the URL of all vacant positions
+func (source) List(ctx context.Context) (listings []Listing, err error) {
+
+	c := tea.NewCollector(ctx)
+
+	c.OnHTML("div#job_DOKTORANDEN a", func(e *colly.HTMLElement) {
+		listings = append(listings, Listing{URL: e.Request.AbsoluteURL(e.Attr("href"))})
+
+	})
+
+	err = c.Visit(vacantPositionsUrl)
+
+	return listings, err
+}
+
+// Get the details of position
+func (source) Detail(ctx context.Context, listing Listing) (Position, error) {
+	position := listing.Position()
+
+	c := tea.NewCollector(ctx)
+
+	c.OnHTML("div.text h1", func(e *colly.HTMLElement) {
+		position.Title = strings.TrimSpace(e.Text)
+
+	})
+
+	c.OnHTML("div.text p", func(e *colly.HTMLElement) {
+
+		per := e.DOM.Prev().Text()
+		if strings.Contains(per, "Application up to") {
+			position.Date = strings.TrimSpace(e.Text)
+		} else {
+			position.Description += fmt.Sprintln(strings.TrimSpace(e.Text))
+		}
+
+	})
+
+	err := c.Visit(position.URL)
+
+	// Remove nun-English positions, But we keep them in our DB so we won't visit them again!
+	if position.Date == "" {
+		position.Description = ""
+		position.Title = ""
+	}
+
+	return position, err
+
+}
+
+func init() {
+	tea.Register(source{}, tea.Location{City: "Karlsruhe", Country: "Germany", Language: "en"})
+}
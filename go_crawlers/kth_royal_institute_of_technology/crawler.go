@@ -0,0 +1,99 @@
+package kth_royal_institute_of_technology
+
+import (
+	"context"
+	"strings"
+
+	"fenjan.ai-hue.ir/tea"
+	"github.com/gocolly/colly"
+)
+
+// Set the university name, the database table name for this university, and the url of vacant positions
+var uniName string = "KTH Royal Institute of Technology"
+var tableName string = "kth_se"
+var vacantPositionsUrl string = "https://www.kth.se/en/om/work-at-kth/doktorander-1.572201"
+
+// Get Position and Listing types from tea helper package
+type Position = tea.Position
+type Listing = tea.Listing
+
+// source implements tea.Source for the university website
+type source struct{}
+
+func (source) Name() string         { return uniName }
+func (source) TableName() string    { return tableName }
+func (source) DateIsDeadline() bool { return true }
+
+// get the URL, Title, Department and Date of all vacant positions
+func (source) List(ctx context.Context) (listings []Listing, err error) {
+
+	c := tea.NewCollector(ctx)
+
+	c.OnHTML("tr", func(e *colly.HTMLElement) {
+		title := e.ChildText("a")
+		url := e.ChildAttr("a", "href")
+		date := e.ChildText("td:last-child")
+
+		// The department is in the middle column when the table has one
+		department := ""
+		if e.DOM.Find("td").Length() > 2 {
+			department = e.ChildText("td:nth-child(2)")
+		}
+
+		if url != "" {
+			listings = append(listings, Listing{Title: title, URL: url, Date: date, Department: department})
+		}
+
+	})
+
+	err = c.Visit(vacantPositionsUrl)
+
+	return listings, err
+}
+
+// Get the details of position
+func (source) Detail(ctx context.Context, listing Listing) (Position, error) {
+	position := listing.Position()
+
+	c := tea.NewCollector(ctx)
+
+	c.OnHTML("div.content-wrap", func(e *colly.HTMLElement) {
+		position.Description = e.Text
+
+		junkText := `$(document).ready(function() {
+			function initializeAddThis() {
+				$.getScript('//s7.addthis.com/js/300/addthis_widget.js#pubid=mynetwork', function() {
+					addthis_config = {
+						username: 'mynetwork',
+						ui_language  : 'en'
+					}
+					var addthis_share = {
+						url_transforms : {
+							add: {
+								referring_service:
+								'{{code}}'
+																			}
+						}
+					}
+				});
+			}
+	
+							document.addEventListener('cookieConsent', function(event) {
+					if (event.detail.cookieClassification.includes('Marketing')) {
+						initializeAddThis();
+					}
+				});
+					});
+	`
+		position.Description = strings.Replace(position.Description, junkText, "", -1)
+	})
+
+	err := c.Visit(position.URL)
+
+	return position, err
+
+}
+
+func init() {
+	tea.Register(source{}, tea.Location{City: "Stockholm", Country: "Sweden", Language: "en"})
+}
@@ -0,0 +1,11 @@
+package kth_royal_institute_of_technology
+
+import (
+	"testing"
+
+	"fenjan.ai-hue.ir/tea/fixture"
+)
+
+func TestCrawl(t *testing.T) {
+	fixture.Test(t, source{}, "testdata")
+}
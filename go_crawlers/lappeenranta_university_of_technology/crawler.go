@@ -0,0 +1,74 @@
+package lappeenranta_university_of_technology
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"fenjan.ai-hue.ir/tea"
+	"github.com/gocolly/colly"
+)
+
+// Set the university name, the database table name for this university, and the url of vacant positions
+var uniName string = "Lappeenranta University of Technology"
+var tableName string = "lut_fi"
+var vacantPositionsUrl string = "https://lut.rekrytointi.com/paikat/index.php?o=A_LOJ&list=2"
+
+// LUT calls its doctoral students junior researchers or doctoral candidates in the titles
+var titleRules = tea.TitleRules{Target: []string{"Junior researcher", "PhD", "Ph.D.", "doctoral candidate"}}
+
+// Get Position and Listing types from tea helper package
+type Position = tea.Position
+type Listing = tea.Listing
+
+// source implements tea.Source for the university website
+type source struct{}
+
+func (source) Name() string               { return uniName }
+func (source) TableName() string          { return tableName }
+func (source) DateIsDeadline() bool       { return true }
+func (source) TitleRules() tea.TitleRules { return titleRules }
+
+// get the URL and Dates of all vacant positions
+func (source) List(ctx context.Context) (listings []Listing, err error) {
+
+	c := tea.NewCollector(ctx)
+
+	c.OnHTML("div.auto_list.auto_list_open_jobs tr", func(e *colly.HTMLElement) {
+		date := e.ChildText("td:last-child")
+		url := e.Request.AbsoluteURL(e.ChildAttr("td:last-child a", "href"))
+
+		if date != "" && url != "" {
+			listings = append(listings, Listing{URL: url[:strings.Index(url, "&rspvt=")], Date: date})
+		}
+	})
+
+	err = c.Visit(vacantPositionsUrl)
+
+	return listings, err
+}
+
+// Get the details of position
+func (source) Detail(ctx context.Context, listing Listing) (Position, error) {
+	position := listing.Position()
+
+	c := tea.NewCollector(ctx)
+
+	c.OnHTML("div.job_page h1", func(e *colly.HTMLElement) {
+		position.Title = strings.TrimSpace(e.Text)
+	})
+
+	c.OnHTML("div.job_description", func(e *colly.HTMLElement) {
+		position.Description += fmt.Sprintln(strings.TrimSpace(e.Text))
+	})
+
+	err := c.Visit(position.URL)
+
+	return position, err
+
+}
+
+func init() {
+	// The positions are written in Finnish or English
+	tea.Register(source{}, tea.Location{City: "Lappeenranta", Country: "Finland"})
+}
@@ -0,0 +1,83 @@
+package lund_university
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"fenjan.ai-hue.ir/tea"
+	"github.com/gocolly/colly"
+)
+
+// Set the university name, the database table name for this university, and the url of vacant positions
+var uniName string = "Lund University"
+var tableName string = "lunduniversity_lu_se"
+var vacantPositionsUrl string = "https://www.lunduniversity.lu.se/vacancies"
+
+// Lund lists all vacancies of the university, doctoral students are the ones kept
+var titleRules = tea.TitleRules{Target: []string{"Doctoral Student", "PhD", "Ph.D."}, Forbidden: []string{"Postdoctoral"}}
+
+// Get Position and Listing types from tea helper package
+type Position = tea.Position
+type Listing = tea.Listing
+
+// source implements tea.Source for the university website
+type source struct{}
+
+func (source) Name() string               { return uniName }
+func (source) TableName() string          { return tableName }
+func (source) DateIsDeadline() bool       { return true }
+func (source) TitleRules() tea.TitleRules { return titleRules }
+
+// The website blocks fast crawlers, so the requests are made one by one with a random wait of 1 to 5 seconds
+func (source) CollectorOptions() []tea.CollectorOption {
+	return []tea.CollectorOption{tea.WithRateLimit(1, time.Second, 4*time.Second)}
+}
+
+// get the URL of all vacant positions
+func (source) List(ctx context.Context) (listings []Listing, err error) {
+
+	c := tea.NewCollector(ctx)
+
+	c.OnHTML("tbody.vacancies-list__table--body", func(e *colly.HTMLElement) {
+		e.ForEach("a[href]", func(_ int, a *colly.HTMLElement) {
+			listings = append(listings, Listing{URL: a.Attr("href"), Title: strings.TrimSpace(a.Text)})
+		})
+	})
+
+	err = c.Visit(vacantPositionsUrl)
+
+	return listings, err
+}
+
+// Get the details of position
+func (source) Detail(ctx context.Context, listing Listing) (Position, error) {
+	position := listing.Position()
+
+	c := tea.NewCollector(ctx)
+
+	c.OnHTML("div.content-wrap h1", func(e *colly.HTMLElement) {
+		position.Title = strings.TrimSpace(e.Text)
+
+	})
+
+	c.OnHTML("tr.quick-info-ends td", func(e *colly.HTMLElement) {
+		position.Date = strings.TrimSpace(e.Text)
+	})
+
+	c.OnHTML("div.job-desc.mb", func(e *colly.HTMLElement) {
+
+		position.Description += fmt.Sprintln(strings.TrimSpace(strings.ToValidUTF8(e.Text, "")))
+
+	})
+
+	err := c.Visit(position.URL)
+
+	return position, err
+
+}
+
+func init() {
+	tea.Register(source{}, tea.Location{City: "Lund", Country: "Sweden", Language: "en"})
+}
@@ -0,0 +1,117 @@
+package match
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+)
+
+// DB reads the customers and positions, and keeps track of the sent emails, in the database of the crawlers.
+// The queries work on both MySQL and SQLite.
+type DB struct {
+	db *sql.DB
+}
+
+// NewDB returns a DB using the connection, e.g. the one of a tea.SQLStore
+func NewDB(db *sql.DB) *DB {
+	return &DB{db: db}
+}
+
+// Customers returns all customers of the customers table
+func (d *DB) Customers() ([]Customer, error) {
+	rows, err := d.db.Query("SELECT name, email, keywords FROM customers")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var customers []Customer
+	for rows.Next() {
+		var name, keywords sql.NullString
+		var customer Customer
+		if err := rows.Scan(&name, &customer.Email, &keywords); err != nil {
+			return nil, err
+		}
+		customer.Name = name.String
+		// The keywords are a JSON list
+		if keywords.Valid && keywords.String != "" {
+			if err := json.Unmarshal([]byte(keywords.String), &customer.Keywords); err != nil {
+				return nil, fmt.Errorf("invalid keywords of customer %s: %w", customer.Email, err)
+			}
+		}
+		customers = append(customers, customer)
+	}
+	return customers, rows.Err()
+}
+
+// OpenPositions returns the positions of a source that are not closed with their research fields
+func (d *DB) OpenPositions(source string) ([]Position, error) {
+	rows, err := d.db.Query("SELECT id, source, title, url, description, date, language, legacy_id FROM positions WHERE source = ? AND closed_on IS NULL ORDER BY id", source)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var positions []Position
+	for rows.Next() {
+		var position Position
+		var date, language sql.NullString
+		var legacyID sql.NullInt64
+		if err := rows.Scan(&position.ID, &position.Source, &position.Title, &position.URL, &position.Description, &date, &language, &legacyID); err != nil {
+			return nil, err
+		}
+		position.Date, position.Language, position.LegacyID = date.String, language.String, legacyID.Int64
+		positions = append(positions, position)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return positions, d.addFields(source, positions)
+}
+
+// addFields sets the research fields of the positions of a source from the position_fields table
+func (d *DB) addFields(source string, positions []Position) error {
+	rows, err := d.db.Query("SELECT url, field FROM position_fields WHERE source = ? ORDER BY url, field", source)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	fields := map[string][]string{}
+	for rows.Next() {
+		var url, field string
+		if err := rows.Scan(&url, &field); err != nil {
+			return err
+		}
+		fields[url] = append(fields[url], field)
+	}
+	for i := range positions {
+		positions[i].Fields = fields[positions[i].URL]
+	}
+	return rows.Err()
+}
+
+// WasSent reports whether the sent email with the id is in the keep_track_of_sent_emails table
+func (d *DB) WasSent(id string) (bool, error) {
+	var count int
+	err := d.db.QueryRow("SELECT COUNT(*) FROM keep_track_of_sent_emails WHERE id = ?", id).Scan(&count)
+	return count > 0, err
+}
+
+// MarkSent adds the sent emails to the keep_track_of_sent_emails table
+func (d *DB) MarkSent(sent []SentEmail) error {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, email := range sent {
+		_, err := tx.Exec("REPLACE INTO keep_track_of_sent_emails (id, source, customer_email, position_id) VALUES (?, ?, ?, ?)",
+			email.ID, email.Source, email.CustomerEmail, email.PositionID)
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
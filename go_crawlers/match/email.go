@@ -0,0 +1,109 @@
+package match
+
+import (
+	"fmt"
+	"html"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"fenjan.ai-hue.ir/tea"
+)
+
+// DefaultTemplatesDir is the directory of the email templates and images, shared with the Python scripts
+var DefaultTemplatesDir = filepath.Join(tea.ProjectRootPath, "..", "utils")
+
+// imagesBaseURL is where the images of the templates directory are served from
+const imagesBaseURL = "https://ai-hue.ir/fenjan_phd_finder"
+
+const footer = `Developed by <a href="https://hue-salari.ir/" rel="noopener" style="text-decoration: none; color: #52a150;" target="_blank">Hue (MohammadHossein) Salari</a>`
+
+// Composer renders the emails with the email_template.html and position_template.html templates,
+// whose "&..._place_holder" placeholders are replaced like utils/compose_email.py does
+type Composer struct {
+	emailTemplate    string
+	positionTemplate string
+	logos            []string
+	greetingImages   []string
+}
+
+// NewComposer reads the templates and the names of the images from the directory
+func NewComposer(dir string) (*Composer, error) {
+	emailTemplate, err := os.ReadFile(filepath.Join(dir, "email_template.html"))
+	if err != nil {
+		return nil, err
+	}
+	positionTemplate, err := os.ReadFile(filepath.Join(dir, "position_template.html"))
+	if err != nil {
+		return nil, err
+	}
+	c := &Composer{emailTemplate: string(emailTemplate), positionTemplate: string(positionTemplate)}
+	if c.logos, err = imageNames(filepath.Join(dir, "images", "logo")); err != nil {
+		return nil, err
+	}
+	if c.greetingImages, err = imageNames(filepath.Join(dir, "images", "greeting")); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func imageNames(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			names = append(names, entry.Name())
+		}
+	}
+	if len(names) == 0 {
+		return nil, fmt.Errorf("there is no image in %s", dir)
+	}
+	return names, nil
+}
+
+// Subject returns the subject of the email of the positions of a university
+func Subject(universityName string) string {
+	return "PhD Positions from " + universityName
+}
+
+// Compose returns the HTML of the email of the matched positions of a university to a customer
+func (c *Composer) Compose(customer Customer, universityName string, matches []Match, today time.Time) string {
+	var positions strings.Builder
+	for i, match := range matches {
+		positions.WriteString(strings.NewReplacer(
+			"&position_title_place_holder", fmt.Sprintf("Ph.D. Position %d", i+1),
+			"&position_summery_place_holder", positionSummary(match),
+		).Replace(c.positionTemplate))
+	}
+
+	greeting := fmt.Sprintf(`Dear %s,<br>
+    I am pleased to present to you a list of Ph.D. positions that have been advertised on %s in the past 24 hours.<br><br>
+    %s`, html.EscapeString(customer.Name), html.EscapeString(universityName), today.Format("January 02, 2006"))
+
+	return strings.NewReplacer(
+		"&heder_logo_place_holder", imagesBaseURL+"/logo/"+c.logos[rand.Intn(len(c.logos))],
+		"&greeting_image_place_holder", imagesBaseURL+"/greeting/"+c.greetingImages[rand.Intn(len(c.greetingImages))],
+		"&title_place_holder", "Ph.D. positions from "+html.EscapeString(universityName),
+		"&greeting_place_holder", greeting,
+		"&position_template_place_holder", positions.String(),
+		"&footer_place_holder", footer,
+	).Replace(c.emailTemplate)
+}
+
+// positionSummary returns the title, matched keywords, date and link of a position in the position template
+func positionSummary(match Match) string {
+	position := match.Position
+	summary := fmt.Sprintf(`<span style="font-weight: bold">Title:</span> %s <br> <span style="font-weight: bold">Matched Keywords:</span> <br> <span style="color:#68677b">%s</span>`,
+		html.EscapeString(position.Title), html.EscapeString(strings.Join(match.Keywords, ", ")))
+	if position.Date != "" {
+		summary += fmt.Sprintf(` <br> <span style="font-weight: bold">Date:</span> %s`, html.EscapeString(position.Date))
+	}
+	url := html.EscapeString(position.URL)
+	summary += fmt.Sprintf(` <br><br>🔗: <a href="%s" rel="noopener" style="text-decoration: underline; color: #8a3b8f;" target="_blank">%s</a>`, url, url)
+	return summary
+}
@@ -0,0 +1,193 @@
+// Package match finds the positions saved by the crawlers that match the keywords of the customers,
+// and emails them a digest of the positions they haven't received yet.
+//
+// It is the Go port of advertised_on_universities_website.py and the utils/search.py,
+// utils/compose_email.py and utils/send_email.py modules, and uses the same tables and email templates.
+package match
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"fenjan.ai-hue.ir/crawlers/query"
+	"fenjan.ai-hue.ir/tea"
+)
+
+// Customer is a subscriber of the newsletter
+type Customer struct {
+	Name     string
+	Email    string
+	Keywords []string
+}
+
+// SearchKeywords returns the variants of the keywords of the customer that are searched for in the positions,
+// the keywords and the plain keywords without their spaces, e.g. "deep learning" also matches "#deeplearning".
+// A keyword is a query of the query package, e.g. "robotics AND NOT title:postdoc".
+func (c Customer) SearchKeywords() []string {
+	var keywords []string
+	seen := map[string]bool{}
+	add := func(keyword string) {
+		if keyword != "" && !seen[keyword] {
+			seen[keyword] = true
+			keywords = append(keywords, keyword)
+		}
+	}
+	for _, keyword := range c.Keywords {
+		add(keyword)
+	}
+	for _, keyword := range c.Keywords {
+		if !query.HasSyntax(keyword) {
+			add(strings.ReplaceAll(keyword, " ", ""))
+		}
+	}
+	return keywords
+}
+
+// Position is an open position saved by the crawlers
+type Position struct {
+	ID          int64
+	Source      string
+	Title       string
+	URL         string
+	Description string
+	Date        string
+	// Language is the ISO 639-1 code of the language of the position, empty if it's unknown
+	Language string
+	// LegacyID is the id of the position in the old per university table it was copied from, 0 if it has none
+	LegacyID int64
+	// Fields are the IDs of the research fields the position is tagged with, see the taxonomy package
+	Fields []string
+
+	// document is the analyzed title and description, kept in the slice of positions so they are analyzed
+	// once for all customers
+	document *query.Document
+}
+
+// Document returns the analyzed title and description of the position the keywords are searched in
+func (p *Position) Document() *query.Document {
+	if p.document == nil {
+		p.document = query.NewDocument(p.Title, p.Description, p.Language)
+	}
+	return p.document
+}
+
+// TrackingKey returns the table name and id used to keep track of the sent emails.
+// Positions copied from the old per university tables keep their old table name and id,
+// so the emails sent before are not sent again.
+func (p Position) TrackingKey() (string, int64) {
+	if p.LegacyID != 0 {
+		return p.Source, p.LegacyID
+	}
+	return "positions", p.ID
+}
+
+// SentEmail is a row of the keep_track_of_sent_emails table, a position sent to a customer
+type SentEmail struct {
+	ID            string
+	Source        string
+	CustomerEmail string
+	PositionID    int64
+}
+
+// SentEmailOf returns the row that records sending the position to the customer
+func SentEmailOf(customer Customer, position Position) SentEmail {
+	table, id := position.TrackingKey()
+	return SentEmail{
+		ID:            fmt.Sprintf("%s_%s_%d", customer.Email, table, id),
+		Source:        table,
+		CustomerEmail: customer.Email,
+		PositionID:    id,
+	}
+}
+
+// University is a source of positions with the rules of the titles of the positions sent to the customers
+type University struct {
+	Name      string
+	TableName string
+	// TitleRules are the rules of the source, the crawlers already reject the titles that break them,
+	// they are checked again for the positions saved before the source had them
+	TitleRules tea.TitleRules
+}
+
+// AllowsTitle reports whether a position with the title may be sent to the customers
+func (u University) AllowsTitle(title string) bool {
+	reason, _ := u.TitleRules.Reject(title)
+	return reason == ""
+}
+
+// Match is a position that matches the keywords of a customer
+type Match struct {
+	Position Position
+	// Keywords are the keywords of the customer found in the position
+	Keywords []string
+}
+
+// keywordQuery is a keyword of a customer parsed as a query
+type keywordQuery struct {
+	keyword string
+	query   *query.Query
+	// field is the ID of the research field the keyword names, e.g. "machine-learning" for "#ML",
+	// empty if it names none
+	field string
+}
+
+// parseKeywords parses the keywords, the keywords without any word, e.g. "#", are skipped
+func parseKeywords(keywords []string) []keywordQuery {
+	var queries []keywordQuery
+	// A field is only searched for its first keyword, so "deep learning" and its variant "deeplearning"
+	// aren't both found in a tagged position
+	fields := map[string]bool{}
+	for _, keyword := range keywords {
+		q, err := query.ParseKeyword(keyword)
+		if err != nil {
+			continue
+		}
+		kq := keywordQuery{keyword: keyword, query: q}
+		if !query.HasSyntax(keyword) && tea.Tagger != nil {
+			if field, ok := tea.Tagger.Lookup(keyword); ok && !fields[field.ID] {
+				fields[field.ID] = true
+				kq.field = field.ID
+			}
+		}
+		queries = append(queries, kq)
+	}
+	return queries
+}
+
+// searchPosition returns the keywords whose query matches the document, or that name a research field
+// the position is tagged with, so "machine learning" also matches a position about "maschinelles Lernen"
+func searchPosition(doc *query.Document, fields []string, queries []keywordQuery) []string {
+	var found []string
+	for _, q := range queries {
+		if q.query.Match(doc) || (q.field != "" && slices.Contains(fields, q.field)) {
+			found = append(found, q.keyword)
+		}
+	}
+	return found
+}
+
+// SearchKeywords returns the keywords that match a position with the title and description in the language.
+// The words are matched whole and by their stem in the language, case-insensitively, so "AI" doesn't match
+// "maintain" and "network" matches "networks", and the plain keywords are phrases, see query.ParseKeyword.
+func SearchKeywords(title, description, language string, keywords []string) []string {
+	return searchPosition(query.NewDocument(title, description, language), nil, parseKeywords(keywords))
+}
+
+// Positions returns the positions of the university whose title or description has a keyword of the customer,
+// or that are tagged with the research field a keyword names
+func Positions(customer Customer, university University, positions []Position) []Match {
+	queries := parseKeywords(customer.SearchKeywords())
+	var matches []Match
+	for i := range positions {
+		position := &positions[i]
+		if !university.AllowsTitle(position.Title) {
+			continue
+		}
+		found := searchPosition(position.Document(), position.Fields, queries)
+		if len(found) > 0 {
+			matches = append(matches, Match{Position: *position, Keywords: found})
+		}
+	}
+	return matches
+}
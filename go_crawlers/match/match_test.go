@@ -0,0 +1,163 @@
+package match
+
+import (
+	"database/sql"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"fenjan.ai-hue.ir/tea"
+	_ "modernc.org/sqlite"
+)
+
+func TestPositions(t *testing.T) {
+	customer := Customer{Email: "a@example.com", Keywords: []string{"deep learning", "#AI"}}
+	university := University{TableName: "tum_de", TitleRules: tea.TitleRules{Target: []string{"Doctoral", "PhD"}, Forbidden: []string{"Postdoctoral"}}}
+	positions := []Position{
+		{ID: 1, Title: "PhD position in Deep Learning"},
+		{ID: 2, Title: "Postdoctoral researcher in deep learning"},
+		{ID: 3, Title: "Research engineer", Description: "deep learning"},
+		{ID: 4, Title: "Doctoral candidate", Description: "We use #DeepLearning for remote sensing"},
+		{ID: 5, Title: "PhD position in history"},
+	}
+
+	var got []int64
+	for _, match := range Positions(customer, university, positions) {
+		got = append(got, match.Position.ID)
+	}
+	if !reflect.DeepEqual(got, []int64{1, 4}) {
+		t.Errorf("matched positions %v, want [1 4]", got)
+	}
+	if keywords := Positions(customer, university, positions[3:4])[0].Keywords; !reflect.DeepEqual(keywords, []string{"deeplearning"}) {
+		t.Errorf("matched keywords %v, want the keyword without spaces", keywords)
+	}
+}
+
+func TestPositionsWholeWordsAndQueries(t *testing.T) {
+	customer := Customer{Email: "a@example.com", Keywords: []string{"AI", "title:network AND NOT wireless"}}
+	positions := []Position{
+		{ID: 1, Title: "PhD position", Description: "You will maintain the cluster"},
+		{ID: 2, Title: "Doctoral student in AI-driven chemistry"},
+		{ID: 3, Title: "PhD student in Neural Networks"},
+		{ID: 4, Title: "PhD student in Wireless Networks"},
+		{ID: 5, Title: "PhD student in Robotics", Description: "Network of partners"},
+	}
+
+	got := map[int64][]string{}
+	for _, match := range Positions(customer, University{TableName: "kth_se"}, positions) {
+		got[match.Position.ID] = match.Keywords
+	}
+	want := map[int64][]string{2: {"AI"}, 3: {"title:network AND NOT wireless"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("matched %v, want %v", got, want)
+	}
+}
+
+func TestPositionsTaggedWithTheFieldOfAKeyword(t *testing.T) {
+	customer := Customer{Email: "a@example.com", Keywords: []string{"deep learning", "#AI AND NOT title:postdoc"}}
+	positions := []Position{
+		{ID: 1, Title: "Doktorand (m/w/d) maschinelles Lernen", Language: "de", Fields: []string{"computer-science", "machine-learning"}},
+		{ID: 2, Title: "Doktorand (m/w/d) maschinelles Lernen", Language: "de"},
+		{ID: 3, Title: "PhD student in Chemistry", Fields: []string{"artificial-intelligence", "computer-science"}},
+	}
+
+	got := map[int64][]string{}
+	for _, match := range Positions(customer, University{TableName: "tum_de"}, positions) {
+		got[match.Position.ID] = match.Keywords
+	}
+	// The keywords with a query syntax are only matched against the text
+	want := map[int64][]string{1: {"deep learning"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("matched %v, want %v", got, want)
+	}
+}
+
+func TestTrackingKey(t *testing.T) {
+	customer := Customer{Email: "a@example.com"}
+	if sent := SentEmailOf(customer, Position{ID: 7, Source: "kth_se"}); sent.ID != "a@example.com_positions_7" {
+		t.Errorf("sent email id %q", sent.ID)
+	}
+	if sent := SentEmailOf(customer, Position{ID: 7, Source: "kth_se", LegacyID: 3}); sent.ID != "a@example.com_kth_se_3" || sent.Source != "kth_se" {
+		t.Errorf("sent email of a copied position %+v, want its old table and id", sent)
+	}
+}
+
+// recordingSender keeps the sent emails
+type recordingSender struct {
+	emails map[string]string
+}
+
+func (s *recordingSender) Send(to, subject, html string) error {
+	s.emails[to] = html
+	return nil
+}
+
+func TestNotifySendsNewPositionsOnce(t *testing.T) {
+	db, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "fenjan.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	db.SetMaxOpenConns(1)
+	store := tea.NewSQLiteStore(db)
+	defer store.Close()
+	if err := store.CreateTablesIfNotExist(); err != nil {
+		t.Fatal(err)
+	}
+	_, err = db.Exec(`INSERT INTO customers (name, email, expiration_date, keywords) VALUES
+		('Ada', 'ada@example.com', '2030-01-01', '["computer vision"]'),
+		('Bob', 'bob@example.com', '2030-01-01', '["chemistry"]')`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = store.SavePositions("kth_se", []tea.Position{
+		{Title: "PhD student in Computer Vision", URL: "https://kth.se/1", Description: "Funded position", Date: "2030-03-01", Fields: []string{"computer-science", "computer-vision"}},
+		{Title: "PhD student in Robotics", URL: "https://kth.se/2", Description: "Uses computer vision"},
+		{Title: "PhD student in Chemistry", URL: "https://kth.se/3", Description: "Funded position"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	positions, err := NewDB(db).OpenPositions("kth_se")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(positions) != 3 || !reflect.DeepEqual(positions[0].Fields, []string{"computer-science", "computer-vision"}) || positions[1].Fields != nil {
+		t.Errorf("open positions %+v, want the fields of the first one", positions)
+	}
+
+	composer, err := NewComposer(DefaultTemplatesDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sender := &recordingSender{emails: map[string]string{}}
+	notifier := &Notifier{DB: NewDB(db), Composer: composer, Sender: sender}
+	university := University{Name: "KTH Royal Institute of Technology", TableName: "kth_se"}
+
+	report, err := notifier.Notify(university)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Customers != 2 || report.Positions != 3 {
+		t.Errorf("report %+v, want 3 positions sent to 2 customers", report)
+	}
+	email := sender.emails["ada@example.com"]
+	for _, want := range []string{"Dear Ada", "Ph.D. Position 2", "https://kth.se/2", "computer vision", "2030-03-01"} {
+		if !strings.Contains(email, want) {
+			t.Errorf("the email of Ada doesn't have %q", want)
+		}
+	}
+	if strings.Contains(email, "https://kth.se/3") {
+		t.Error("the email of Ada has a position that doesn't match the keywords")
+	}
+
+	// The positions are sent only once
+	sender.emails = map[string]string{}
+	if report, err = notifier.Notify(university); err != nil {
+		t.Fatal(err)
+	}
+	if report.Customers != 0 || len(sender.emails) != 0 {
+		t.Errorf("sent %d emails again", len(sender.emails))
+	}
+}
@@ -0,0 +1,86 @@
+package match
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"fenjan.ai-hue.ir/logger"
+)
+
+// customerKey is the log attribute of the email address of a customer
+const customerKey = "customer"
+
+// Notifier emails the customers the open positions of the universities that match their keywords
+// and that they haven't received yet
+type Notifier struct {
+	DB       *DB
+	Composer *Composer
+	Sender   Sender
+	// DryRun doesn't record the emails as sent, so the same positions are sent again next time. It still
+	// sends them with Sender, so set it with a Sender that doesn't email the customers, like the one
+	// writing the emails to files in fenjan-notify --dry-run
+	DryRun bool
+}
+
+// Report counts what Notify did for a university
+type Report struct {
+	Customers int // customers that got an email
+	Positions int // positions sent, counted once per customer
+}
+
+// Notify emails the customers the new matching positions of the university. A failure to send
+// the email of one customer doesn't stop sending the others, all failures are returned together.
+func (n *Notifier) Notify(university University) (Report, error) {
+	log := logger.ForSource(university.TableName)
+	var report Report
+
+	customers, err := n.DB.Customers()
+	if err != nil {
+		return report, fmt.Errorf("error getting the customers: %w", err)
+	}
+	positions, err := n.DB.OpenPositions(university.TableName)
+	if err != nil {
+		return report, fmt.Errorf("error getting the positions of %s: %w", university.TableName, err)
+	}
+
+	var errs []error
+	for _, customer := range customers {
+		var matches []Match
+		var sent []SentEmail
+		for _, match := range Positions(customer, university, positions) {
+			email := SentEmailOf(customer, match.Position)
+			wasSent, err := n.DB.WasSent(email.ID)
+			if err != nil {
+				return report, err
+			}
+			if !wasSent {
+				matches = append(matches, match)
+				sent = append(sent, email)
+			}
+		}
+		if len(matches) == 0 {
+			log.Debug("Nothing to send 😿.", customerKey, customer.Email)
+			continue
+		}
+
+		html := n.Composer.Compose(customer, university.Name, matches, time.Now())
+		if err := n.Sender.Send(customer.Email, Subject(university.Name), html); err != nil {
+			log.Error("Sending the email failed ☠️!", customerKey, customer.Email, logger.ErrorKey, err)
+			errs = append(errs, fmt.Errorf("error sending the email to %s: %w", customer.Email, err))
+			continue
+		}
+		log.Info("Sent the email 📧.", customerKey, customer.Email, "positions", len(matches))
+		report.Customers++
+		report.Positions += len(matches)
+
+		if n.DryRun {
+			continue
+		}
+		if err := n.DB.MarkSent(sent); err != nil {
+			// The positions would be sent again to everyone, so stop
+			return report, errors.Join(append(errs, fmt.Errorf("error recording the email to %s: %w", customer.Email, err))...)
+		}
+	}
+	return report, errors.Join(errs...)
+}
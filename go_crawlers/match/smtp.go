@@ -0,0 +1,124 @@
+package match
+
+import (
+	"bytes"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"os"
+	"strconv"
+	"time"
+)
+
+// Sender sends an HTML email
+type Sender interface {
+	Send(to, subject, html string) error
+}
+
+// Mailer sends the emails with an SMTP server. Port 465 uses implicit TLS like Gmail's SMTP_SSL,
+// the other ports use STARTTLS when the server supports it.
+type Mailer struct {
+	Host     string
+	Port     int
+	Address  string // the address the emails are sent from, also the username
+	Password string
+}
+
+// MailerFromEnv returns the Mailer of the EMAIL_ADDRESS and EMAIL_PASSWORD environment variables,
+// the server is smtp.gmail.com:465 unless SMTP_HOST or SMTP_PORT are set
+func MailerFromEnv() (*Mailer, error) {
+	m := &Mailer{
+		Host:     os.Getenv("SMTP_HOST"),
+		Port:     465,
+		Address:  os.Getenv("EMAIL_ADDRESS"),
+		Password: os.Getenv("EMAIL_PASSWORD"),
+	}
+	if m.Address == "" || m.Password == "" {
+		return nil, errors.New("EMAIL_ADDRESS or EMAIL_PASSWORD is not set")
+	}
+	if m.Host == "" {
+		m.Host = "smtp.gmail.com"
+	}
+	if port := os.Getenv("SMTP_PORT"); port != "" {
+		var err error
+		if m.Port, err = strconv.Atoi(port); err != nil {
+			return nil, fmt.Errorf("invalid SMTP_PORT %q", port)
+		}
+	}
+	return m, nil
+}
+
+// Send sends the HTML email to the address
+func (m *Mailer) Send(to, subject, html string) error {
+	message, err := Message(m.Address, to, subject, html)
+	if err != nil {
+		return err
+	}
+	addr := net.JoinHostPort(m.Host, strconv.Itoa(m.Port))
+	auth := smtp.PlainAuth("", m.Address, m.Password, m.Host)
+	if m.Port != 465 {
+		return smtp.SendMail(addr, auth, m.Address, []string{to}, message)
+	}
+
+	conn, err := tls.DialWithDialer(&net.Dialer{Timeout: 30 * time.Second}, "tcp", addr, &tls.Config{ServerName: m.Host})
+	if err != nil {
+		return err
+	}
+	client, err := smtp.NewClient(conn, m.Host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+	if err := client.Auth(auth); err != nil {
+		return err
+	}
+	if err := client.Mail(m.Address); err != nil {
+		return err
+	}
+	if err := client.Rcpt(to); err != nil {
+		return err
+	}
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(message); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
+
+// Message returns the MIME message of an HTML email, the body is quoted-printable
+// as the lines of the templates are longer than SMTP allows
+func Message(from, to, subject, html string) ([]byte, error) {
+	if _, err := mail.ParseAddress(to); err != nil {
+		return nil, fmt.Errorf("invalid address %q: %w", to, err)
+	}
+
+	var message bytes.Buffer
+	fmt.Fprintf(&message, "From: %s\r\n", from)
+	fmt.Fprintf(&message, "To: %s\r\n", to)
+	fmt.Fprintf(&message, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject))
+	fmt.Fprintf(&message, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	message.WriteString("MIME-Version: 1.0\r\n")
+	message.WriteString("Content-Type: text/html; charset=utf-8\r\n")
+	message.WriteString("Content-Transfer-Encoding: quoted-printable\r\n\r\n")
+
+	w := quotedprintable.NewWriter(&message)
+	if _, err := w.Write([]byte(html)); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return message.Bytes(), nil
+}
@@ -0,0 +1,21 @@
+package match
+
+import "fenjan.ai-hue.ir/tea"
+
+// UniversityOf returns the university of a source with its title rules, if it has any
+func UniversityOf(source tea.Source) University {
+	university := University{Name: source.Name(), TableName: source.TableName()}
+	if filter, ok := source.(tea.TitleFilter); ok {
+		university.TitleRules = filter.TitleRules()
+	}
+	return university
+}
+
+// Universities returns the universities of all registered sources
+func Universities() []University {
+	var universities []University
+	for _, source := range tea.Sources() {
+		universities = append(universities, UniversityOf(source))
+	}
+	return universities
+}
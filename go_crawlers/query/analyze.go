@@ -0,0 +1,112 @@
+package query
+
+import (
+	"strings"
+	"unicode"
+)
+
+// stemmer is the language whose stemmer compares the words of a document and of the queries matched against it
+type stemmer int
+
+const (
+	english stemmer = iota
+	german
+)
+
+// term is a word of a text or a query with its English and German stems, two words are the same term
+// when they have the same stem in the language of the document they are compared in
+type term struct {
+	word, english, german string
+}
+
+func (t term) matches(other term, s stemmer) bool {
+	if s == german {
+		return t.german == other.german
+	}
+	return t.english == other.english
+}
+
+// analyze splits the text into its lower case words of letters and digits, every other character
+// separates words, so "#AI" is "ai" and "AI-driven" is "ai" and "driven"
+func analyze(text string) []term {
+	var terms []term
+	for _, word := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		terms = append(terms, term{word: word, english: stemEnglish(word), german: stemGerman(word)})
+	}
+	return terms
+}
+
+// germanStopWords and englishStopWords are common words that tell the language of a text whose language is unknown
+var (
+	germanStopWords  = map[string]bool{"und": true, "der": true, "die": true, "das": true, "mit": true, "für": true, "von": true, "ist": true, "wir": true, "sie": true, "zu": true, "den": true, "eine": true}
+	englishStopWords = map[string]bool{"the": true, "and": true, "of": true, "to": true, "with": true, "for": true, "is": true, "we": true, "you": true, "an": true, "are": true, "will": true}
+)
+
+// stemmerOf returns the stemmer of a document in the language, an ISO 639-1 code. The texts in an unknown
+// language are German if they have more German than English stop words, English otherwise, and the texts
+// in the other languages are compared with the English stemmer, which mostly leaves their words as they are.
+func stemmerOf(language string, terms ...[]term) stemmer {
+	switch strings.ToLower(language) {
+	case "de":
+		return german
+	case "":
+		count := 0
+		for _, field := range terms {
+			for _, t := range field {
+				if germanStopWords[t.word] {
+					count++
+				} else if englishStopWords[t.word] {
+					count--
+				}
+			}
+		}
+		if count > 0 {
+			return german
+		}
+	}
+	return english
+}
+
+// Document is the analyzed title and description of a position that queries are matched against
+type Document struct {
+	title, description []term
+	stemmer            stemmer
+}
+
+// NewDocument analyzes the title and description of a position written in the language, an ISO 639-1 code
+// like "en" or "de", or "" if it is unknown
+func NewDocument(title, description, language string) *Document {
+	doc := &Document{title: analyze(title), description: analyze(description)}
+	doc.stemmer = stemmerOf(language, doc.title, doc.description)
+	return doc
+}
+
+// field returns the terms of a field of the document
+func (d *Document) field(f Field) [][]term {
+	switch f {
+	case Title:
+		return [][]term{d.title}
+	case Description:
+		return [][]term{d.description}
+	}
+	return [][]term{d.title, d.description}
+}
+
+// contains reports whether the terms have the phrase, its terms one after the other, compared by the stemmer
+func contains(terms, phrase []term, s stemmer) bool {
+	for i := 0; i+len(phrase) <= len(terms); i++ {
+		found := true
+		for j, t := range phrase {
+			if !terms[i+j].matches(t, s) {
+				found = false
+				break
+			}
+		}
+		if found {
+			return true
+		}
+	}
+	return false
+}
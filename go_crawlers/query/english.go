@@ -0,0 +1,288 @@
+package query
+
+import "strings"
+
+// stemEnglish returns the stem of a lower case English word with the Snowball English (Porter2) stemmer,
+// e.g. "learning" and "learned" are "learn", "universities" is "univers"
+func stemEnglish(word string) string {
+	if len([]rune(word)) <= 2 {
+		return word
+	}
+	if stem, ok := englishExceptions[word]; ok {
+		return stem
+	}
+
+	w := []rune(strings.TrimPrefix(word, "'"))
+	// A y that is a consonant is marked as Y
+	for i, r := range w {
+		if r == 'y' && (i == 0 || isEnglishVowel(w[i-1])) {
+			w[i] = 'Y'
+		}
+	}
+	s := &englishWord{w: w}
+	s.markRegions()
+
+	s.step0()
+	s.step1a()
+	if englishInvariants1a[string(s.w)] {
+		return string(s.w)
+	}
+	s.step1b()
+	s.step1c()
+	s.step2()
+	s.step3()
+	s.step4()
+	s.step5()
+	return strings.ReplaceAll(string(s.w), "Y", "y")
+}
+
+// englishExceptions are the words the stemmer doesn't handle, with their stem
+var englishExceptions = map[string]string{
+	"skis": "ski", "skies": "sky", "dying": "die", "lying": "lie", "tying": "tie",
+	"idly": "idl", "gently": "gentl", "ugly": "ugli", "early": "earli", "only": "onli", "singly": "singl",
+	"sky": "sky", "news": "news", "howe": "howe", "atlas": "atlas", "cosmos": "cosmos", "bias": "bias", "andes": "andes",
+}
+
+// englishInvariants1a are the words left as they are after step 1a
+var englishInvariants1a = map[string]bool{
+	"inning": true, "outing": true, "canning": true, "herring": true, "earring": true,
+	"proceed": true, "exceed": true, "succeed": true,
+}
+
+func isEnglishVowel(r rune) bool {
+	return strings.ContainsRune("aeiouy", r)
+}
+
+// englishWord is a word being stemmed with its regions R1 and R2, which are the indexes they start at
+type englishWord struct {
+	w      []rune
+	r1, r2 int
+}
+
+func (s *englishWord) markRegions() {
+	s.r1 = len(s.w)
+	word := string(s.w)
+	for _, prefix := range []string{"gener", "commun", "arsen"} {
+		if strings.HasPrefix(word, prefix) {
+			s.r1 = len([]rune(prefix))
+			break
+		}
+	}
+	if s.r1 == len(s.w) {
+		s.r1 = regionAfter(s.w, 0, isEnglishVowel)
+	}
+	s.r2 = regionAfter(s.w, s.r1, isEnglishVowel)
+}
+
+// regionAfter returns the index after the first non-vowel following a vowel from start, or the length of the word
+func regionAfter(w []rune, start int, isVowel func(rune) bool) int {
+	for i := start + 1; i < len(w); i++ {
+		if !isVowel(w[i]) && isVowel(w[i-1]) {
+			return i + 1
+		}
+	}
+	return len(w)
+}
+
+func (s *englishWord) hasSuffix(suffix string) bool {
+	return strings.HasSuffix(string(s.w), suffix)
+}
+
+// longestSuffix returns the longest of the suffixes the word ends with, they must be sorted by length
+// from the longest, and the index the suffix starts at
+func (s *englishWord) longestSuffix(suffixes ...string) (string, int) {
+	for _, suffix := range suffixes {
+		if s.hasSuffix(suffix) {
+			return suffix, len(s.w) - len([]rune(suffix))
+		}
+	}
+	return "", -1
+}
+
+func (s *englishWord) replace(suffix, replacement string) {
+	s.w = append(s.w[:len(s.w)-len([]rune(suffix))], []rune(replacement)...)
+}
+
+func (s *englishWord) hasVowelBefore(end int) bool {
+	for _, r := range s.w[:end] {
+		if isEnglishVowel(r) {
+			return true
+		}
+	}
+	return false
+}
+
+// endsWithShortSyllable reports whether the word ends with a vowel followed by a non-vowel other than w, x
+// or Y and preceded by a non-vowel, or is a vowel at the beginning followed by a non-vowel
+func (s *englishWord) endsWithShortSyllable() bool {
+	w := s.w
+	n := len(w)
+	if n == 2 {
+		return isEnglishVowel(w[0]) && !isEnglishVowel(w[1])
+	}
+	return n >= 3 && !isEnglishVowel(w[n-3]) && isEnglishVowel(w[n-2]) &&
+		!isEnglishVowel(w[n-1]) && w[n-1] != 'w' && w[n-1] != 'x' && w[n-1] != 'Y'
+}
+
+func (s *englishWord) isShort() bool {
+	return s.r1 >= len(s.w) && s.endsWithShortSyllable()
+}
+
+func (s *englishWord) step0() {
+	if suffix, _ := s.longestSuffix("'s'", "'s", "'"); suffix != "" {
+		s.replace(suffix, "")
+	}
+}
+
+func (s *englishWord) step1a() {
+	suffix, start := s.longestSuffix("sses", "ied", "ies", "us", "ss", "s")
+	switch suffix {
+	case "sses":
+		s.replace(suffix, "ss")
+	case "ied", "ies":
+		if start > 1 {
+			s.replace(suffix, "i")
+		} else {
+			s.replace(suffix, "ie")
+		}
+	case "s":
+		// The s is deleted if there is a vowel before the letter preceding it
+		if start >= 2 && s.hasVowelBefore(start-1) {
+			s.replace(suffix, "")
+		}
+	}
+}
+
+func (s *englishWord) step1b() {
+	suffix, start := s.longestSuffix("eedly", "ingly", "edly", "eed", "ing", "ed")
+	switch suffix {
+	case "":
+		return
+	case "eed", "eedly":
+		if start >= s.r1 {
+			s.replace(suffix, "ee")
+		}
+		return
+	}
+	if !s.hasVowelBefore(start) {
+		return
+	}
+	s.replace(suffix, "")
+	switch {
+	case s.hasSuffix("at") || s.hasSuffix("bl") || s.hasSuffix("iz"):
+		s.w = append(s.w, 'e')
+	case s.endsWithDouble():
+		s.w = s.w[:len(s.w)-1]
+	case s.isShort():
+		s.w = append(s.w, 'e')
+	}
+}
+
+func (s *englishWord) endsWithDouble() bool {
+	for _, double := range []string{"bb", "dd", "ff", "gg", "mm", "nn", "pp", "rr", "tt"} {
+		if s.hasSuffix(double) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *englishWord) step1c() {
+	n := len(s.w)
+	if n > 2 && (s.w[n-1] == 'y' || s.w[n-1] == 'Y') && !isEnglishVowel(s.w[n-2]) {
+		s.w[n-1] = 'i'
+	}
+}
+
+// englishStep2 are the suffixes of step 2 and their replacements, from the longest
+var englishStep2 = [][2]string{
+	{"ization", "ize"}, {"ational", "ate"}, {"fulness", "ful"}, {"ousness", "ous"}, {"iveness", "ive"},
+	{"tional", "tion"}, {"biliti", "ble"}, {"lessli", "less"},
+	{"entli", "ent"}, {"ation", "ate"}, {"alism", "al"}, {"aliti", "al"}, {"ousli", "ous"}, {"iviti", "ive"}, {"fulli", "ful"},
+	{"enci", "ence"}, {"anci", "ance"}, {"abli", "able"}, {"izer", "ize"}, {"ator", "ate"}, {"alli", "al"},
+	{"bli", "ble"}, {"ogi", "og"}, {"li", ""},
+}
+
+func (s *englishWord) step2() {
+	for _, rule := range englishStep2 {
+		if !s.hasSuffix(rule[0]) {
+			continue
+		}
+		start := len(s.w) - len([]rune(rule[0]))
+		if start < s.r1 {
+			return
+		}
+		switch rule[0] {
+		case "ogi":
+			if start == 0 || s.w[start-1] != 'l' {
+				return
+			}
+		case "li":
+			if start == 0 || !strings.ContainsRune("cdeghkmnrt", s.w[start-1]) {
+				return
+			}
+		}
+		s.replace(rule[0], rule[1])
+		return
+	}
+}
+
+// englishStep3 are the suffixes of step 3 and their replacements, from the longest
+var englishStep3 = [][2]string{
+	{"ational", "ate"}, {"tional", "tion"}, {"alize", "al"}, {"icate", "ic"}, {"iciti", "ic"},
+	{"ative", ""}, {"ical", "ic"}, {"ness", ""}, {"ful", ""},
+}
+
+func (s *englishWord) step3() {
+	for _, rule := range englishStep3 {
+		if !s.hasSuffix(rule[0]) {
+			continue
+		}
+		start := len(s.w) - len([]rune(rule[0]))
+		if start < s.r1 || rule[0] == "ative" && start < s.r2 {
+			return
+		}
+		s.replace(rule[0], rule[1])
+		return
+	}
+}
+
+// englishStep4 are the suffixes deleted by step 4, from the longest
+var englishStep4 = []string{
+	"ement", "ance", "ence", "able", "ible", "ment", "ant", "ent", "ism", "ate", "iti", "ous", "ive", "ize", "ion",
+	"al", "er", "ic",
+}
+
+func (s *englishWord) step4() {
+	suffix, start := s.longestSuffix(englishStep4...)
+	if suffix == "" || start < s.r2 {
+		return
+	}
+	if suffix == "ion" && (start == 0 || s.w[start-1] != 's' && s.w[start-1] != 't') {
+		return
+	}
+	s.replace(suffix, "")
+}
+
+func (s *englishWord) step5() {
+	n := len(s.w)
+	switch {
+	case n == 0:
+	case s.w[n-1] == 'e':
+		if n-1 >= s.r2 {
+			s.w = s.w[:n-1]
+			return
+		}
+		if n-1 >= s.r1 {
+			// The e stays after a short syllable
+			rest := &englishWord{w: s.w[:n-1]}
+			if !rest.endsWithShortSyllable() {
+				s.w = s.w[:n-1]
+			}
+		}
+	case s.w[n-1] == 'l':
+		if n-1 >= s.r2 && n >= 2 && s.w[n-2] == 'l' {
+			s.w = s.w[:n-1]
+		}
+	}
+}
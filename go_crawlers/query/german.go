@@ -0,0 +1,121 @@
+package query
+
+import "strings"
+
+// stemGerman returns the stem of a lower case German word with the Snowball German stemmer,
+// e.g. "Lernen" and "lernt" are "lern", "Häuser" is "haus"
+func stemGerman(word string) string {
+	w := []rune(strings.ReplaceAll(word, "ß", "ss"))
+	// A u or y between vowels is a consonant and is marked as U or Y
+	for i := 1; i < len(w)-1; i++ {
+		if (w[i] == 'u' || w[i] == 'y') && isGermanVowel(w[i-1]) && isGermanVowel(w[i+1]) {
+			w[i] = w[i] - 'a' + 'A'
+		}
+	}
+
+	// R1 starts after the first non-vowel following a vowel, but not before the fourth letter
+	r1 := regionAfter(w, 0, isGermanVowel)
+	r2 := regionAfter(w, r1, isGermanVowel)
+	if r1 < 3 {
+		r1 = 3
+	}
+
+	w = germanStep1(w, r1)
+	w = germanStep2(w, r1)
+	w = germanStep3(w, r1, r2)
+
+	stem := strings.NewReplacer("U", "u", "Y", "y", "ä", "a", "ö", "o", "ü", "u").Replace(string(w))
+	return stem
+}
+
+func isGermanVowel(r rune) bool {
+	return strings.ContainsRune("aeiouyäöü", r)
+}
+
+// isValidSEnding reports whether an s can be removed after the letter
+func isValidSEnding(r rune) bool {
+	return strings.ContainsRune("bdfghklmnrt", r)
+}
+
+// isValidStEnding reports whether an st can be removed after the letter
+func isValidStEnding(r rune) bool {
+	return strings.ContainsRune("bdfghklmnt", r)
+}
+
+// germanSuffix returns the longest of the suffixes the word ends with, they must be sorted by length
+// from the longest, and the index the suffix starts at
+func germanSuffix(w []rune, suffixes ...string) (string, int) {
+	word := string(w)
+	for _, suffix := range suffixes {
+		if strings.HasSuffix(word, suffix) {
+			return suffix, len(w) - len([]rune(suffix))
+		}
+	}
+	return "", -1
+}
+
+func germanStep1(w []rune, r1 int) []rune {
+	suffix, start := germanSuffix(w, "ern", "em", "er", "en", "es", "e", "s")
+	switch {
+	case suffix == "" || start < r1:
+		return w
+	case suffix == "s":
+		if start > 0 && isValidSEnding(w[start-1]) {
+			return w[:start]
+		}
+		return w
+	}
+	w = w[:start]
+	// "Kenntnisse" is "kenntnis"
+	if (suffix == "en" || suffix == "es" || suffix == "e") && strings.HasSuffix(string(w), "niss") {
+		w = w[:len(w)-1]
+	}
+	return w
+}
+
+func germanStep2(w []rune, r1 int) []rune {
+	suffix, start := germanSuffix(w, "est", "en", "er", "st")
+	switch {
+	case suffix == "" || start < r1:
+		return w
+	case suffix == "st":
+		// The st is preceded by a valid ending which is preceded by at least 3 letters
+		if start >= 4 && isValidStEnding(w[start-1]) {
+			return w[:start]
+		}
+		return w
+	}
+	return w[:start]
+}
+
+func germanStep3(w []rune, r1, r2 int) []rune {
+	suffix, start := germanSuffix(w, "heit", "isch", "keit", "lich", "end", "ung", "ig", "ik")
+	if suffix == "" || start < r2 {
+		return w
+	}
+	precededBy := func(s string) bool { return strings.HasSuffix(string(w[:start]), s) }
+	switch suffix {
+	case "end", "ung":
+		w = w[:start]
+		if strings.HasSuffix(string(w), "ig") && len(w)-2 >= r2 && !strings.HasSuffix(string(w), "eig") {
+			w = w[:len(w)-2]
+		}
+	case "ig", "ik", "isch":
+		if !precededBy("e") {
+			w = w[:start]
+		}
+	case "lich", "heit":
+		w = w[:start]
+		if (strings.HasSuffix(string(w), "er") || strings.HasSuffix(string(w), "en")) && len(w)-2 >= r1 {
+			w = w[:len(w)-2]
+		}
+	case "keit":
+		w = w[:start]
+		if strings.HasSuffix(string(w), "lich") && len(w)-4 >= r2 {
+			w = w[:len(w)-4]
+		} else if strings.HasSuffix(string(w), "ig") && len(w)-2 >= r2 {
+			w = w[:len(w)-2]
+		}
+	}
+	return w
+}
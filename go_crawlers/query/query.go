@@ -0,0 +1,420 @@
+// Package query matches the subscriptions of the customers against the title and description of the positions
+// with full-text queries, e.g.
+//
+//	"machine learning" AND (robotics OR "computer vision") NOT title:postdoc
+//
+// The texts are split into whole words, so "AI" doesn't match "maintain", and the words are compared by their
+// Snowball stem in the language of the position, so "learning" matches "learned" in an English position and
+// "Netzwerke" matches "Netzwerk" in a German one.
+//
+// A query is made of words and quoted phrases, combined with AND, OR, NOT and parentheses. The operators are
+// written in upper case, and words written one after the other are combined with AND, which binds tighter than
+// OR. A word, phrase or parenthesized query prefixed with title: or description: only matches that field.
+package query
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Field is the part of a position a query is matched against
+type Field int
+
+const (
+	// Any matches the title or the description
+	Any Field = iota
+	Title
+	Description
+)
+
+var fieldNames = map[string]Field{"title": Title, "description": Description}
+
+func (f Field) String() string {
+	switch f {
+	case Title:
+		return "title"
+	case Description:
+		return "description"
+	}
+	return ""
+}
+
+// Query is a parsed query
+type Query struct {
+	text string
+	root node
+}
+
+// Parse parses the text of a query, the error tells what is wrong and where
+func Parse(text string) (*Query, error) {
+	tokens, err := lex(text)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("empty query")
+	}
+	p := &parser{tokens: tokens}
+	root, err := p.parseOr(Any)
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("unexpected %s", p.tokens[p.pos])
+	}
+	return &Query{text: text, root: root}, nil
+}
+
+// ParseKeyword parses a keyword of a customer. The keywords subscribed with before the queries, like
+// "deep learning", are phrases, so a keyword without any quote, parenthesis, operator or field is a phrase.
+// A keyword that isn't a valid query is a phrase too, so the subscription still matches something.
+func ParseKeyword(keyword string) (*Query, error) {
+	if !HasSyntax(keyword) {
+		return Phrase(keyword)
+	}
+	if q, err := Parse(keyword); err == nil {
+		return q, nil
+	}
+	return Phrase(keyword)
+}
+
+// Phrase returns the query that matches the words of the text one after the other in the title or description
+func Phrase(text string) (*Query, error) {
+	terms := analyze(text)
+	if len(terms) == 0 {
+		return nil, fmt.Errorf("%q has no words", text)
+	}
+	return &Query{text: text, root: &phraseNode{terms: terms}}, nil
+}
+
+// HasSyntax reports whether the keyword uses any syntax of the queries, a keyword without it is a phrase
+func HasSyntax(keyword string) bool {
+	if strings.ContainsAny(keyword, `"()`) {
+		return true
+	}
+	for _, word := range strings.Fields(keyword) {
+		if word == "AND" || word == "OR" || word == "NOT" {
+			return true
+		}
+		if name, _, ok := strings.Cut(word, ":"); ok {
+			if _, ok := fieldNames[strings.ToLower(name)]; ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Match reports whether the document matches the query
+func (q *Query) Match(doc *Document) bool {
+	return q.root.match(doc)
+}
+
+// MatchText reports whether a position with the title and description in the language matches the query
+func (q *Query) MatchText(title, description, language string) bool {
+	return q.Match(NewDocument(title, description, language))
+}
+
+// Text returns the text the query was parsed from
+func (q *Query) Text() string {
+	return q.text
+}
+
+// String returns the parsed query with its stems and explicit operators, e.g.
+// (title:"machin learn" OR robot) AND NOT postdoc
+func (q *Query) String() string {
+	return q.root.String()
+}
+
+// node is a node of the syntax tree of a query
+type node interface {
+	match(doc *Document) bool
+	String() string
+}
+
+// phraseNode matches one or more terms one after the other, a word is a phrase of one term
+type phraseNode struct {
+	terms []term
+	field Field
+}
+
+func (n *phraseNode) match(doc *Document) bool {
+	for _, terms := range doc.field(n.field) {
+		if contains(terms, n.terms, doc.stemmer) {
+			return true
+		}
+	}
+	return false
+}
+
+func (n *phraseNode) String() string {
+	var stems []string
+	for _, t := range n.terms {
+		stems = append(stems, t.english)
+	}
+	s := strings.Join(stems, " ")
+	if len(stems) > 1 {
+		s = `"` + s + `"`
+	}
+	if n.field != Any {
+		s = n.field.String() + ":" + s
+	}
+	return s
+}
+
+type andNode struct{ children []node }
+
+func (n *andNode) match(doc *Document) bool {
+	for _, child := range n.children {
+		if !child.match(doc) {
+			return false
+		}
+	}
+	return true
+}
+
+func (n *andNode) String() string { return join(n.children, " AND ") }
+
+type orNode struct{ children []node }
+
+func (n *orNode) match(doc *Document) bool {
+	for _, child := range n.children {
+		if child.match(doc) {
+			return true
+		}
+	}
+	return false
+}
+
+func (n *orNode) String() string { return join(n.children, " OR ") }
+
+type notNode struct{ child node }
+
+func (n *notNode) match(doc *Document) bool { return !n.child.match(doc) }
+
+func (n *notNode) String() string { return "NOT " + join([]node{n.child}, "") }
+
+// join joins the children with the operator, the children combining other nodes are parenthesized
+func join(children []node, operator string) string {
+	var parts []string
+	for _, child := range children {
+		switch child.(type) {
+		case *andNode, *orNode:
+			parts = append(parts, "("+child.String()+")")
+		default:
+			parts = append(parts, child.String())
+		}
+	}
+	return strings.Join(parts, operator)
+}
+
+// tokenKind is the kind of a token of a query
+type tokenKind int
+
+const (
+	wordToken tokenKind = iota
+	phraseToken
+	fieldToken // a field name followed by a colon, e.g. title:
+	andToken
+	orToken
+	notToken
+	openToken
+	closeToken
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	// pos is the index of the first character of the token in the query
+	pos int
+}
+
+// String returns the token and its position for the errors, e.g. "OR" at 12
+func (t token) String() string {
+	if t.kind == fieldToken {
+		return fmt.Sprintf("%q at %d", t.text+":", t.pos+1)
+	}
+	return fmt.Sprintf("%q at %d", t.text, t.pos+1)
+}
+
+// lex splits a query into its tokens
+func lex(text string) ([]token, error) {
+	var tokens []token
+	runes := []rune(text)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			tokens = append(tokens, token{kind: openToken, text: "(", pos: i})
+			i++
+		case r == ')':
+			tokens = append(tokens, token{kind: closeToken, text: ")", pos: i})
+			i++
+		case r == '"':
+			end := i + 1
+			for end < len(runes) && runes[end] != '"' {
+				end++
+			}
+			if end == len(runes) {
+				return nil, fmt.Errorf("the quote at %d isn't closed", i+1)
+			}
+			tokens = append(tokens, token{kind: phraseToken, text: string(runes[i+1 : end]), pos: i})
+			i = end + 1
+		default:
+			end := i
+			for end < len(runes) && !unicode.IsSpace(runes[end]) && !strings.ContainsRune(`()"`, runes[end]) {
+				end++
+			}
+			word := string(runes[i:end])
+			tokens = append(tokens, wordTokens(word, i)...)
+			i = end
+		}
+	}
+	return tokens, nil
+}
+
+// wordTokens returns the tokens of a word, which is an operator, a field name with the word after the colon,
+// or a word to search for
+func wordTokens(word string, pos int) []token {
+	switch word {
+	case "AND":
+		return []token{{kind: andToken, text: word, pos: pos}}
+	case "OR":
+		return []token{{kind: orToken, text: word, pos: pos}}
+	case "NOT":
+		return []token{{kind: notToken, text: word, pos: pos}}
+	}
+	if name, rest, ok := strings.Cut(word, ":"); ok {
+		if _, ok := fieldNames[strings.ToLower(name)]; ok {
+			tokens := []token{{kind: fieldToken, text: strings.ToLower(name), pos: pos}}
+			if rest != "" {
+				tokens = append(tokens, token{kind: wordToken, text: rest, pos: pos + len([]rune(name)) + 1})
+			}
+			return tokens
+		}
+	}
+	return []token{{kind: wordToken, text: word, pos: pos}}
+}
+
+// parser is a recursive descent parser of the grammar
+//
+//	or     = and { "OR" and }
+//	and    = unary { ["AND"] unary }
+//	unary  = "NOT" unary | [field ":"] atom
+//	atom   = word | phrase | "(" or ")"
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peek() (token, bool) {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos], true
+	}
+	return token{}, false
+}
+
+func (p *parser) parseOr(field Field) (node, error) {
+	left, err := p.parseAnd(field)
+	if err != nil {
+		return nil, err
+	}
+	children := []node{left}
+	for {
+		t, ok := p.peek()
+		if !ok || t.kind != orToken {
+			break
+		}
+		p.pos++
+		right, err := p.parseAnd(field)
+		if err != nil {
+			return nil, err
+		}
+		children = append(children, right)
+	}
+	if len(children) == 1 {
+		return left, nil
+	}
+	return &orNode{children: children}, nil
+}
+
+func (p *parser) parseAnd(field Field) (node, error) {
+	left, err := p.parseUnary(field)
+	if err != nil {
+		return nil, err
+	}
+	children := []node{left}
+	for {
+		t, ok := p.peek()
+		if !ok || t.kind == orToken || t.kind == closeToken {
+			break
+		}
+		if t.kind == andToken {
+			p.pos++
+		}
+		right, err := p.parseUnary(field)
+		if err != nil {
+			return nil, err
+		}
+		children = append(children, right)
+	}
+	if len(children) == 1 {
+		return left, nil
+	}
+	return &andNode{children: children}, nil
+}
+
+func (p *parser) parseUnary(field Field) (node, error) {
+	t, ok := p.peek()
+	if !ok {
+		return nil, fmt.Errorf("the query ends where a word is expected")
+	}
+	switch t.kind {
+	case notToken:
+		p.pos++
+		child, err := p.parseUnary(field)
+		if err != nil {
+			return nil, err
+		}
+		return &notNode{child: child}, nil
+	case fieldToken:
+		if field != Any {
+			return nil, fmt.Errorf("the field %s is inside the field %s", t, field)
+		}
+		p.pos++
+		return p.parseAtom(fieldNames[t.text])
+	}
+	return p.parseAtom(field)
+}
+
+func (p *parser) parseAtom(field Field) (node, error) {
+	t, ok := p.peek()
+	if !ok {
+		return nil, fmt.Errorf("the query ends where a word is expected")
+	}
+	switch t.kind {
+	case wordToken, phraseToken:
+		p.pos++
+		terms := analyze(t.text)
+		if len(terms) == 0 {
+			return nil, fmt.Errorf("%s has no words", t)
+		}
+		// A word like "machine-learning" is a phrase of its words
+		return &phraseNode{terms: terms, field: field}, nil
+	case openToken:
+		p.pos++
+		inner, err := p.parseOr(field)
+		if err != nil {
+			return nil, err
+		}
+		if next, ok := p.peek(); !ok || next.kind != closeToken {
+			return nil, fmt.Errorf("the parenthesis %s isn't closed", t)
+		}
+		p.pos++
+		return inner, nil
+	}
+	return nil, fmt.Errorf("unexpected %s", t)
+}
@@ -0,0 +1,139 @@
+package query
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestStemEnglish(t *testing.T) {
+	for word, want := range map[string]string{
+		"caresses": "caress", "ponies": "poni", "ties": "tie", "cries": "cri", "gas": "gas", "gaps": "gap",
+		"running": "run", "hopping": "hop", "hoping": "hope", "learning": "learn", "learned": "learn",
+		"universities": "univers", "university": "univers", "generalization": "general", "generously": "generous",
+		"consistently": "consist", "consolation": "consol", "consolatory": "consolatori", "conspirators": "conspir",
+		"constable": "constabl", "constancy": "constanc", "conspiracy": "conspiraci", "conspicuously": "conspicu",
+		"biological": "biolog", "biology": "biolog", "computational": "comput", "computer": "comput",
+		"networks": "network", "skies": "sky", "proceed": "proceed", "maintain": "maintain", "ai": "ai",
+	} {
+		if got := stemEnglish(word); got != want {
+			t.Errorf("stemEnglish(%q) = %q, want %q", word, got, want)
+		}
+	}
+}
+
+func TestStemGerman(t *testing.T) {
+	for word, want := range map[string]string{
+		"aufeinander": "aufeinand", "aufeinanderfolgenden": "aufeinanderfolg", "aufeinanderfolgten": "aufeinanderfolgt",
+		"aufeinanderschlügen": "aufeinanderschlug", "aufenthaltes": "aufenthalt", "auferlegen": "auferleg",
+		"auferstanden": "auferstand", "lernen": "lern", "doktoranden": "doktorand", "netzwerke": "netzwerk",
+		"kenntnisse": "kenntnis", "häuser": "haus",
+	} {
+		if got := stemGerman(word); got != want {
+			t.Errorf("stemGerman(%q) = %q, want %q", word, got, want)
+		}
+	}
+}
+
+func TestMatch(t *testing.T) {
+	title := "Doctoral researcher in Machine Learning for Robotics"
+	description := "You will maintain our robots and publish on deep neural networks."
+	tests := []struct {
+		query string
+		want  bool
+	}{
+		{"AI", false}, // only inside "maintain"
+		{"robot", true},
+		{"machine learning", true},
+		{`"machine learned"`, true},
+		{`"learning machine"`, false},
+		{"title:robotics", true},
+		{"title:network", false},
+		{"description:network", true},
+		{"postdoc OR robotics", true},
+		{"robotics AND NOT postdoc", true},
+		{"robotics NOT learning", false},
+		{"NOT (postdoc OR chemistry)", true},
+		{`title:("deep learning" OR "machine learning") AND description:neural`, true},
+		{"machine-learning", true},
+		{"#DeepLearning", false},
+		{"chemistry OR physics robotics", false},
+	}
+	for _, test := range tests {
+		q, err := Parse(test.query)
+		if err != nil {
+			t.Errorf("Parse(%q): %v", test.query, err)
+			continue
+		}
+		if got := q.MatchText(title, description, "en"); got != test.want {
+			t.Errorf("%s (parsed as %s) matched %v, want %v", test.query, q, got, test.want)
+		}
+	}
+}
+
+func TestMatchLanguages(t *testing.T) {
+	tests := []struct {
+		query, text, language string
+		want                  bool
+	}{
+		{"Netzwerk", "Kenntnisse in Netzwerken", "de", true},
+		{"Doktorand", "Wir suchen Doktoranden", "de", true},
+		{"learning", "machine learned models", "en", true},
+		// The German stemmer isn't used on English positions
+		{"list", "listen to the students", "en", false},
+		{"mode", "modern methods", "en", false},
+		{"moth", "mother tongue", "en", false},
+		{"rate", "raten", "en", false},
+		{"art", "arten", "en", false},
+		// The language of the positions in an unknown language is told by their stop words
+		{"list", "listen to the students and the staff", "", false},
+		{"Netzwerk", "Wir suchen Kenntnisse in Netzwerken und der Robotik", "", true},
+	}
+	for _, test := range tests {
+		q, err := Parse(test.query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := q.MatchText("", test.text, test.language); got != test.want {
+			t.Errorf("%q in the %q text %q matched %v, want %v", test.query, test.language, test.text, got, test.want)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for query, want := range map[string]string{
+		"":                         "empty query",
+		`"deep learning`:           "the quote at 1 isn't closed",
+		"(robotics OR vision":      `the parenthesis "(" at 1 isn't closed`,
+		"robotics OR":              "the query ends where a word is expected",
+		"robotics )":               `unexpected ")" at 10`,
+		"title:(description:deep)": `the field "description:" at 8 is inside the field title`,
+		"AND robotics":             `unexpected "AND" at 1`,
+	} {
+		_, err := Parse(query)
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("Parse(%q) error %v, want %q", query, err, want)
+		}
+	}
+}
+
+func TestParseKeyword(t *testing.T) {
+	tests := []struct {
+		keyword, want string
+	}{
+		{"deep learning", `"deep learn"`},
+		{"#AI", "ai"},
+		{"deep learning OR vision", "(deep AND learn) OR vision"},
+		{"title:robotics", "title:robot"},
+		{"C++ (advanced", `"c advanc"`},
+	}
+	for _, test := range tests {
+		q, err := ParseKeyword(test.keyword)
+		if err != nil {
+			t.Errorf("ParseKeyword(%q): %v", test.keyword, err)
+			continue
+		}
+		if q.String() != test.want {
+			t.Errorf("ParseKeyword(%q) = %s, want %s", test.keyword, q, test.want)
+		}
+	}
+}
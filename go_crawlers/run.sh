@@ -1,34 +1,14 @@
 #!/bin/bash
 
-# Check if an argument was passed
+# Build the fenjan-crawl binary once and crawl the given universities,
+# or all the registered universities if no table name is passed
+cd "$(dirname "$0")" || exit 1
+
+go build -o bin/fenjan-crawl ./cmd/fenjan-crawl || exit 1
+
 if [ $# -eq 0 ]
 then
-  echo "Please provide a directory path as an argument."
-  exit 1
+  ./bin/fenjan-crawl run --all
+else
+  ./bin/fenjan-crawl run "$@"
 fi
-
-# Set the directory path to the first argument passed
-directory=$1
-
-# Find all the folders in the directory
-folders=$(find $directory -type d)
-
-# Loop through each folder
-for folder in $folders
-do
-  echo "checking $folder for main.go file"
-  # Check if main.go file exists in the folder
-  if [ -e "$folder/main.go" ]
-  then
-    # Change to the folder
-    cd $folder
-
-    # Run the go command
-    go run main.go
-
-    # Return to the original directory
-    cd $directory
-  else
-    echo "main.go not found in $folder"
-  fi
-done
@@ -0,0 +1,40 @@
+// Package sources registers all the university sources in the tea registry
+package sources
+
+import (
+	"fmt"
+	"os"
+
+	"fenjan.ai-hue.ir/crawlers/declarative"
+
+	// Every university package registers its source in its init function
+	_ "fenjan.ai-hue.ir/crawlers/aalto_university"
+	_ "fenjan.ai-hue.ir/crawlers/freie_universitat_berlin"
+	_ "fenjan.ai-hue.ir/crawlers/karlsruhe_institute_of_technology"
+	_ "fenjan.ai-hue.ir/crawlers/kth_royal_institute_of_technology"
+	_ "fenjan.ai-hue.ir/crawlers/lappeenranta_university_of_technology"
+	_ "fenjan.ai-hue.ir/crawlers/lund_university"
+	_ "fenjan.ai-hue.ir/crawlers/technical_university_of_munich"
+	_ "fenjan.ai-hue.ir/crawlers/university_of_eastern_finland"
+	_ "fenjan.ai-hue.ir/crawlers/university_of_helsinki"
+	_ "fenjan.ai-hue.ir/crawlers/university_of_jyvaskyla"
+	_ "fenjan.ai-hue.ir/crawlers/university_of_oulu"
+	_ "fenjan.ai-hue.ir/crawlers/university_of_tampere"
+	_ "fenjan.ai-hue.ir/crawlers/university_of_turku"
+	_ "fenjan.ai-hue.ir/crawlers/uppsala_university"
+	_ "fenjan.ai-hue.ir/crawlers/uva_university_of_amsterdam"
+)
+
+// LoadDefinitionsDir registers the universities of the definition files in the directory set by the
+// FENJAN_DEFINITIONS_DIR environment variable, the declarative package already registers the embedded ones,
+// so more definition files can be added without rebuilding the binary
+func LoadDefinitionsDir() error {
+	dir := os.Getenv("FENJAN_DEFINITIONS_DIR")
+	if dir == "" {
+		return nil
+	}
+	if err := declarative.RegisterDir(dir); err != nil {
+		return fmt.Errorf("error loading the definitions from %s: %w", dir, err)
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+package technical_university_of_munich
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"fenjan.ai-hue.ir/tea"
+	"github.com/gocolly/colly"
+)
+
+// Set the university name, the database table name for this university, and the url of vacant positions
+var uniName string = "Technical University of Munich (TUM)"
+var tableName string = "tum_de"
+var vacantPositionsUrl string = "https://portal.mytum.de/jobs/wissenschaftler/newsboard_view?b_start:int=0&-C="
+
+// TUM advertises every job of the university, only the doctoral ones are kept
+var titleRules = tea.TitleRules{Target: []string{"Doctoral", "PhD", "Ph.D."}, Forbidden: []string{"Postdoctoral"}}
+
+// Get Position and Listing types from tea helper package
+type Position = tea.Position
+type Listing = tea.Listing
+
+// source implements tea.Source for the university website
+type source struct{}
+
+func (source) Name() string               { return uniName }
+func (source) TableName() string          { return tableName }
+func (source) TitleRules() tea.TitleRules { return titleRules }
+
+// get the URL and Dates of all vacant positions
+func (source) List(ctx context.Context) (listings []Listing, err error) {
+	var NumVisitedPages int
+	c := tea.NewCollector(ctx)
+
+	// Find and visit all links
+	c.OnHTML("span.next a", func(e *colly.HTMLElement) {
+		nextPageUrl := e.Request.AbsoluteURL(e.Attr("href"))
+		if NumVisitedPages < 10 {
+			NumVisitedPages += 1
+			c.Visit(nextPageUrl)
+		}
+	})
+
+	c.OnHTML("span", func(e *colly.HTMLElement) {
+
+		link, _ := e.DOM.Find("h6 a").Attr("href")
+		title := strings.TrimSpace(e.DOM.Find("h6 a").Text())
+		dateString := e.DOM.Find("h5").Text()
+
+		if link != "" && dateString != "" {
+			dateList := strings.Split(dateString, ".")
+
+			intYear, _ := strconv.Atoi(dateList[2])
+			intMonth, _ := strconv.Atoi(dateList[1])
+			intDay, _ := strconv.Atoi(dateList[0])
+			date := time.Date(intYear, time.Month(intMonth), intDay, 0, 0, 0, 0, time.UTC)
+
+			pastMonth := time.Now().AddDate(0, -1, 0)
+
+			if date.After(pastMonth) {
+
+				listings = append(listings, Listing{URL: link, Title: title, Date: dateString})
+			}
+		}
+	})
+
+	err = c.Visit(vacantPositionsUrl)
+
+	return listings, err
+}
+
+// Get the details of position
+func (source) Detail(ctx context.Context, listing Listing) (Position, error) {
+	position := listing.Position()
+
+	c := tea.NewCollector(ctx)
+
+	c.OnHTML("h1", func(e *colly.HTMLElement) {
+		position.Title = strings.TrimSpace(e.Text)
+
+	})
+	c.OnHTML("p", func(e *colly.HTMLElement) {
+		position.Description += strings.TrimSpace(e.Text) + "\n"
+	})
+
+	err := c.Visit(position.URL)
+
+	return position, err
+
+}
+
+func init() {
+	// The positions are written in German or English
+	tea.Register(source{}, tea.Location{City: "Munich", Country: "Germany"})
+}
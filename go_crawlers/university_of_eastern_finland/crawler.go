@@ -0,0 +1,78 @@
+package university_of_eastern_finland
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"fenjan.ai-hue.ir/tea"
+	"github.com/gocolly/colly"
+)
+
+// Set the university name, the database table name for this university, and the url of vacant positions
+var uniName string = "University of Eastern Finland"
+var tableName string = "uef_fi"
+var vacantPositionsUrl string = "https://www.uef.fi/en/open-positions"
+
+// Only the doctoral researcher and PhD positions of the job feed are kept
+var titleRules = tea.TitleRules{Target: []string{"Doctoral Researcher", "PhD", "Ph.D."}, Forbidden: []string{"Postdoctoral"}}
+
+// Get Position and Listing types from tea helper package
+type Position = tea.Position
+type Listing = tea.Listing
+
+// source implements tea.Source for the university website
+type source struct{}
+
+func (source) Name() string               { return uniName }
+func (source) TableName() string          { return tableName }
+func (source) DateIsDeadline() bool       { return true }
+func (source) TitleRules() tea.TitleRules { return titleRules }
+
+// get the URL and Dates of all vacant positions
+func (source) List(ctx context.Context) (listings []Listing, err error) {
+
+	c := tea.NewCollector(ctx)
+
+	c.OnHTML("article.rss-feed-item", func(e *colly.HTMLElement) {
+
+		date := strings.Split(strings.TrimSpace(e.ChildText("div.rss-feed-item__content")), "-")[0]
+		date = strings.ReplaceAll(date, "Apply by: ", "")
+
+		url := e.ChildAttr("a", "href")
+
+		listings = append(listings, Listing{URL: url, Date: date})
+
+	})
+
+	err = c.Visit(vacantPositionsUrl)
+
+	return listings, err
+}
+
+// Get the details of position
+func (source) Detail(ctx context.Context, listing Listing) (Position, error) {
+	position := listing.Position()
+
+	c := tea.NewCollector(ctx)
+
+	// Extract title of the position
+	c.OnHTML("div.title", func(e *colly.HTMLElement) {
+		position.Title = strings.TrimSpace(e.Text)
+	})
+
+	// Extract the description of the position
+	c.OnHTML("div.normal", func(e *colly.HTMLElement) {
+		position.Description += fmt.Sprintln(strings.TrimSpace(e.Text))
+	})
+
+	err := c.Visit(position.URL)
+
+	return position, err
+
+}
+
+func init() {
+	// The university has campuses in Joensuu and Kuopio
+	tea.Register(source{}, tea.Location{Country: "Finland", Language: "en"})
+}
@@ -0,0 +1,108 @@
+package university_of_helsinki
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"time"
+
+	"fenjan.ai-hue.ir/tea"
+)
+
+// Set the university name, the database table name for this university, and the url of vacant positions
+var uniName string = "University of Helsinki"
+var tableName string = "helsinki_fi"
+var vacantPositionsUrl string = "https://www.helsinki.fi/en/ajax_get_jobs/en/null/null/null/0"
+
+// The website lists all jobs of the university, keep the doctoral researcher positions and skip the postdoc ones
+var titleRules = tea.TitleRules{Target: []string{"Doctoral Researcher"}, Forbidden: []string{"Postdoctoral"}}
+
+// Get Position and Listing types from tea helper package
+type Position = tea.Position
+type Listing = tea.Listing
+
+// source implements tea.Source for the university website
+type source struct{}
+
+func (source) Name() string               { return uniName }
+func (source) TableName() string          { return tableName }
+func (source) DateIsDeadline() bool       { return true }
+func (source) TitleRules() tea.TitleRules { return titleRules }
+
+// get all vacant positions, the AJAX response already contains their details
+func (source) List(ctx context.Context) ([]Listing, error) {
+
+	// Get html response from the URL, the failed requests are retried like the ones of the collectors
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, vacantPositionsUrl, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := tea.HTTPClient(ctx).Do(req)
+	if err != nil {
+		return nil, &tea.NetworkError{URL: vacantPositionsUrl, Err: err}
+	}
+
+	// Pars the response
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, &tea.StatusError{URL: vacantPositionsUrl, StatusCode: resp.StatusCode, Err: errors.New(resp.Status)}
+	}
+	body, err := ioutil.ReadAll(resp.Body) // response body is []byte
+	if err != nil {
+		return nil, &tea.NetworkError{URL: vacantPositionsUrl, Err: err}
+	}
+	var result []struct {
+		Command  string      `json:"command"`
+		Method   string      `json:"method"`
+		Selector string      `json:"selector"`
+		Data     string      `json:"data"`
+		Settings interface{} `json:"settings"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil { // Parse []byte to the go struct pointer
+		return nil, &tea.ParseError{URL: vacantPositionsUrl, Err: err}
+	}
+	if len(result) == 0 {
+		return nil, &tea.ParseError{URL: vacantPositionsUrl, Err: errors.New("the response has no commands")}
+	}
+	var positionsAjax []struct {
+		Title         string `json:"title"`
+		URL           string `json:"url"`
+		Department    string `json:"department"`
+		Date          string `json:"date"`
+		DateTimestamp int    `json:"date_timestamp"`
+		Description   string `json:"description"`
+		Key           int    `json:"key"`
+	}
+	if err := json.Unmarshal([]byte(result[0].Data), &positionsAjax); err != nil { // Parse []byte to the go struct pointer
+		return nil, &tea.ParseError{URL: vacantPositionsUrl, Err: err}
+	}
+
+	listings := []Listing{}
+
+	for _, position := range positionsAjax {
+		listing := Listing{Title: position.Title, URL: position.URL, Description: position.Description, Date: position.Date,
+			Department: position.Department}
+		if position.Key > 0 {
+			listing.Reference = strconv.Itoa(position.Key)
+		}
+		// The deadline is also given as a unix timestamp, which is more reliable than parsing the date
+		if position.DateTimestamp > 0 {
+			listing.Deadline = time.Unix(int64(position.DateTimestamp), 0).UTC()
+		}
+		listings = append(listings, listing)
+	}
+	return listings, nil
+}
+
+// The listing already has all the details of the position
+func (source) Detail(ctx context.Context, listing Listing) (Position, error) {
+	position := listing.Position()
+	return position, nil
+}
+
+func init() {
+	tea.Register(source{}, tea.Location{City: "Helsinki", Country: "Finland", Language: "en"})
+}
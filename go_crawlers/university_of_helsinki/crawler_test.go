@@ -0,0 +1,93 @@
+package university_of_helsinki
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"fenjan.ai-hue.ir/tea"
+	"fenjan.ai-hue.ir/tea/fakeuni"
+	"fenjan.ai-hue.ir/tea/fixture"
+)
+
+func TestCrawl(t *testing.T) {
+	fixture.Test(t, source{}, "testdata")
+}
+
+// useFakeServer crawls the AJAX endpoint of the fake website until the end of the test
+func useFakeServer(t *testing.T, positions ...fakeuni.Position) *fakeuni.Server {
+	server := fakeuni.New(positions...)
+	previous := vacantPositionsUrl
+	vacantPositionsUrl = server.AjaxURL()
+	t.Cleanup(func() {
+		vacantPositionsUrl = previous
+		server.Close()
+	})
+	return server
+}
+
+func TestCrawlFakeServer(t *testing.T) {
+	useFakeServer(t,
+		fakeuni.Position{ID: "1", Title: "Doctoral Researcher in Ecology", Description: "Four year position.", Department: "Faculty of Biological and Environmental Sciences", Deadline: "2030-03-01"},
+		fakeuni.Position{ID: "2", Title: "Postdoctoral Researcher in Linguistics", Description: "Two year position.", Department: "Faculty of Arts", Deadline: "2030-04-15"},
+	)
+	store := tea.NewMemoryStore()
+
+	result, err := tea.Crawl(context.Background(), store, source{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.New != 1 || result.Rejected != 1 {
+		t.Errorf("crawl: %+v, want 1 new and 1 rejected position", result)
+	}
+	rejections := store.Rejections(tableName)
+	// "Postdoctoral Researcher" has the target keyword "Doctoral Researcher" too
+	if len(rejections) != 1 || rejections[0].Reason != tea.RejectedForbiddenKeyword || rejections[0].Keyword != "Postdoctoral" {
+		t.Errorf("rejections %+v, want the postdoctoral position with a forbidden keyword", rejections)
+	}
+	for _, position := range store.Positions(tableName) {
+		if position.Title == "" || position.Description == "" || position.Department == "" || position.Reference == "" {
+			t.Errorf("missing details of %+v", position)
+		}
+		if position.Title == "Doctoral Researcher in Ecology" && !position.Deadline.Equal(time.Date(2030, 3, 1, 0, 0, 0, 0, time.UTC)) {
+			t.Errorf("the deadline is %s, want 2030-03-01", position.Deadline)
+		}
+	}
+}
+
+func TestCrawlFakeServerTooManyRequests(t *testing.T) {
+	server := useFakeServer(t, fakeuni.Position{ID: "1", Title: "Doctoral Researcher in Ecology", Deadline: "2030-03-01"})
+	server.TooManyRequests("/ajax_get_jobs", 1, "1")
+
+	store := tea.NewMemoryStore()
+
+	start := time.Now()
+	result, err := tea.Crawl(context.Background(), store, source{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.New != 1 {
+		t.Errorf("crawl: %+v, want 1 new position", result)
+	}
+	if n := server.Requests("/ajax_get_jobs"); n != 2 {
+		t.Errorf("requested the AJAX endpoint %d times, want 1 request and 1 retry", n)
+	}
+	if elapsed := time.Since(start); elapsed < time.Second {
+		t.Errorf("the crawl took %s, the Retry-After of 1 second wasn't waited", elapsed)
+	}
+}
+
+func TestCrawlFakeServerLongRetryAfter(t *testing.T) {
+	server := useFakeServer(t, fakeuni.Position{ID: "1", Title: "Doctoral Researcher in Ecology", Deadline: "2030-03-01"})
+	server.TooManyRequests("/ajax_get_jobs", 1, "3600")
+
+	_, err := tea.Crawl(context.Background(), tea.NewMemoryStore(), source{})
+	var statusErr *tea.StatusError
+	if !errors.As(err, &statusErr) || statusErr.StatusCode != 429 {
+		t.Errorf("crawl returned %v, want the 429 status error instead of waiting an hour", err)
+	}
+	if n := server.Requests("/ajax_get_jobs"); n != 1 {
+		t.Errorf("requested the AJAX endpoint %d times, want no retry", n)
+	}
+}
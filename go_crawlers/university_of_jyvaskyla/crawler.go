@@ -0,0 +1,66 @@
+package university_of_jyvaskyla
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"fenjan.ai-hue.ir/tea"
+	"github.com/gocolly/colly"
+)
+
+// Set the university name, the database table name for this university, and the url of vacant positions
+var uniName string = "University of Jyvaskyla"
+var tableName string = "jyu_fi"
+var vacantPositionsUrl string = "https://www.jyu.fi/en/workwithus/open-jobs"
+
+// Get Position and Listing types from tea helper package
+type Position = tea.Position
+type Listing = tea.Listing
+
+// source implements tea.Source for the university website
+type source struct{}
+
+func (source) Name() string         { return uniName }
+func (source) TableName() string    { return tableName }
+func (source) DateIsDeadline() bool { return true }
+
+// get the URL and Dates of all vacant positions
+func (source) List(ctx context.Context) (listings []Listing, err error) {
+
+	c := tea.NewCollector(ctx)
+
+	// Extract URLs and dates of positions
+	c.OnHTML("ul.item-listing li", func(e *colly.HTMLElement) {
+		listings = append(listings, Listing{URL: e.ChildAttr("a", "href"), Date: e.ChildText("p")})
+	})
+
+	err = c.Visit(vacantPositionsUrl)
+
+	return listings, err
+}
+
+// Get the details of position
+func (source) Detail(ctx context.Context, listing Listing) (Position, error) {
+	position := listing.Position()
+
+	c := tea.NewCollector(ctx)
+
+	// Extract title of the position
+	c.OnHTML("div.title", func(e *colly.HTMLElement) {
+		position.Title = strings.TrimSpace(e.Text)
+	})
+
+	// Extract the description of the position
+	c.OnHTML("div.normal", func(e *colly.HTMLElement) {
+		position.Description += fmt.Sprintln(strings.TrimSpace(e.Text))
+	})
+
+	err := c.Visit(position.URL)
+
+	return position, err
+}
+
+func init() {
+	tea.Register(source{}, tea.Location{City: "Jyväskylä", Country: "Finland", Language: "en"})
+}
@@ -0,0 +1,78 @@
+package university_of_oulu
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"fenjan.ai-hue.ir/tea"
+	"github.com/gocolly/colly"
+)
+
+// Set the university name, the database table name for this university, and the url of vacant positions
+var uniName string = "University of Oulu"
+var tableName string = "oulu_fi"
+var vacantPositionsUrl string = "https://www.oulu.fi/en/university/jobs"
+
+// The job list also has postdoc and staff positions
+var titleRules = tea.TitleRules{Target: []string{"Doctoral Researcher", "PhD", "Ph.D."}, Forbidden: []string{"Postdoctoral"}}
+
+// Get Position and Listing types from tea helper package
+type Position = tea.Position
+type Listing = tea.Listing
+
+// source implements tea.Source for the university website
+type source struct{}
+
+func (source) Name() string               { return uniName }
+func (source) TableName() string          { return tableName }
+func (source) DateIsDeadline() bool       { return true }
+func (source) TitleRules() tea.TitleRules { return titleRules }
+
+// get the URL and Dates of all vacant positions
+func (source) List(ctx context.Context) (listings []Listing, err error) {
+
+	c := tea.NewCollector(ctx)
+
+	// Visit the next page
+	c.OnHTML("a.pager__link--next", func(e *colly.HTMLElement) {
+		c.Visit(e.Request.AbsoluteURL(e.Attr("href")))
+	})
+
+	// Get URLs and dates of positions
+	c.OnHTML("section.listing-page-jobs div.grid__item", func(e *colly.HTMLElement) {
+		date := e.ChildText("div.teaser__date")
+		url := e.Request.AbsoluteURL(e.ChildAttr("a", "href"))
+		listings = append(listings, Listing{URL: url, Date: date})
+	})
+
+	err = c.Visit(vacantPositionsUrl)
+
+	return listings, err
+}
+
+// Get the details of position
+func (source) Detail(ctx context.Context, listing Listing) (Position, error) {
+	position := listing.Position()
+
+	c := tea.NewCollector(ctx)
+
+	c.OnHTML("td.title", func(e *colly.HTMLElement) {
+		position.Title = strings.TrimSpace(e.Text)
+
+	})
+
+	c.OnHTML("td.normal", func(e *colly.HTMLElement) {
+
+		position.Description += fmt.Sprintln(strings.TrimSpace(e.Text))
+
+	})
+
+	err := c.Visit(position.URL)
+
+	return position, err
+}
+
+func init() {
+	tea.Register(source{}, tea.Location{City: "Oulu", Country: "Finland", Language: "en"})
+}
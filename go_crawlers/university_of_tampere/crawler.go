@@ -0,0 +1,68 @@
+package university_of_tampere
+
+import (
+	"context"
+	"strings"
+
+	"fenjan.ai-hue.ir/tea"
+	"github.com/gocolly/colly"
+)
+
+// Set the university name, the database table name for this university, and the url of vacant positions
+var uniName string = "University of Tampere"
+var tableName string = "tuni_fi"
+var vacantPositionsUrl string = "https://www.tuni.fi/en/about-us/working-at-tampere-universities/open-positions-at-tampere-university"
+
+// Doctoral researchers are advertised together with postdocs and staff
+var titleRules = tea.TitleRules{Target: []string{"Doctoral Researcher"}, Forbidden: []string{"Postdoctoral"}}
+
+// Get Position and Listing types from tea helper package
+type Position = tea.Position
+type Listing = tea.Listing
+
+// source implements tea.Source for the university website
+type source struct{}
+
+func (source) Name() string               { return uniName }
+func (source) TableName() string          { return tableName }
+func (source) DateIsDeadline() bool       { return true }
+func (source) TitleRules() tea.TitleRules { return titleRules }
+
+// get the URL of all vacant positions
+func (source) List(ctx context.Context) (listings []Listing, err error) {
+
+	c := tea.NewCollector(ctx)
+
+	c.OnHTML("div.RSSFeedLiftup__StyledWrapper-sc-bi69hc-0.fbpMqB a[href]", func(e *colly.HTMLElement) {
+		listings = append(listings, Listing{URL: e.Attr("href")})
+	})
+
+	err = c.Visit(vacantPositionsUrl)
+
+	return listings, err
+}
+
+// Get the details of position
+func (source) Detail(ctx context.Context, listing Listing) (Position, error) {
+	position := listing.Position()
+
+	c := tea.NewCollector(ctx)
+
+	c.OnHTML("h1", func(e *colly.HTMLElement) {
+		position.Title = e.Text
+	})
+	c.OnHTML("p", func(e *colly.HTMLElement) {
+		position.Description += strings.TrimSpace(e.Text) + " "
+	})
+	c.OnHTML("span.se_text_se_date span.se_date", func(e *colly.HTMLElement) {
+		position.Date = strings.TrimSpace(e.Text)
+	})
+
+	err := c.Visit(position.URL)
+
+	return position, err
+}
+
+func init() {
+	tea.Register(source{}, tea.Location{City: "Tampere", Country: "Finland", Language: "en"})
+}
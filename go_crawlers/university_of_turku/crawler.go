@@ -0,0 +1,80 @@
+package university_of_turku
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"fenjan.ai-hue.ir/tea"
+	"github.com/gocolly/colly"
+)
+
+// Set the university name, the database table name for this university, and the url of vacant positions
+var uniName string = "University of Turku"
+var tableName string = "utu_fi"
+var vacantPositionsUrl string = "https://rekry.saima.fi/certiahome/open_jobs_view_new.html?did=5600&jc=14&lang=en"
+
+// Keep the doctoral researcher positions of the job list, which also has postdoc and staff positions
+var titleRules = tea.TitleRules{Target: []string{"Doctoral Researcher"}, Forbidden: []string{"Postdoctoral"}}
+
+// Get Position and Listing types from tea helper package
+type Position = tea.Position
+type Listing = tea.Listing
+
+// source implements tea.Source for the university website
+type source struct{}
+
+func (source) Name() string               { return uniName }
+func (source) TableName() string          { return tableName }
+func (source) DateIsDeadline() bool       { return true }
+func (source) TitleRules() tea.TitleRules { return titleRules }
+
+// get the URL and Dates of all vacant positions
+func (source) List(ctx context.Context) (listings []Listing, err error) {
+	var urls, titles, dates []string
+
+	c := tea.NewCollector(ctx)
+
+	c.OnHTML("td a", func(e *colly.HTMLElement) {
+		urls = append(urls, e.Request.AbsoluteURL(e.Attr("href")))
+		titles = append(titles, strings.TrimSpace(e.Text))
+	})
+	c.OnHTML("td:last-child", func(e *colly.HTMLElement) {
+		dates = append(dates, e.Text)
+	})
+
+	err = c.Visit(vacantPositionsUrl)
+
+	// Links and dates are in separate cells of the same rows
+	for idx, url := range urls {
+		listing := Listing{URL: url, Title: titles[idx]}
+		if idx < len(dates) {
+			listing.Date = dates[idx]
+		}
+		listings = append(listings, listing)
+	}
+
+	return listings, err
+}
+
+// Get the details of position
+func (source) Detail(ctx context.Context, listing Listing) (Position, error) {
+	position := listing.Position()
+
+	c := tea.NewCollector(ctx)
+
+	c.OnHTML("td.title", func(e *colly.HTMLElement) {
+		position.Title = strings.TrimSpace(e.Text)
+	})
+	c.OnHTML("td.normal", func(e *colly.HTMLElement) {
+		position.Description += fmt.Sprintln(strings.TrimSpace(e.Text))
+	})
+
+	err := c.Visit(position.URL)
+
+	return position, err
+}
+
+func init() {
+	tea.Register(source{}, tea.Location{City: "Turku", Country: "Finland", Language: "en"})
+}
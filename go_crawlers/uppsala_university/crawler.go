@@ -0,0 +1,72 @@
+package uppsala_university
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"fenjan.ai-hue.ir/tea"
+	"github.com/gocolly/colly"
+)
+
+// Set the university name, the database table name for this university, and the url of vacant positions
+var uniName string = "Uppsala University"
+var tableName string = "uu_se"
+var vacantPositionsUrl string = "https://www.uu.se/en/about-uu/join-us/jobs/?locationFilter=&positionType=doktorand&sortValue=published"
+
+// Get Position and Listing types from tea helper package
+type Position = tea.Position
+type Listing = tea.Listing
+
+// source implements tea.Source for the university website
+type source struct{}
+
+func (source) Name() string         { return uniName }
+func (source) TableName() string    { return tableName }
+func (source) DateIsDeadline() bool { return true }
+
+// get the URL of all vacant positions
+func (source) List(ctx context.Context) (listings []Listing, err error) {
+
+	c := tea.NewCollector(ctx)
+
+	c.OnHTML("li.list-item a", func(e *colly.HTMLElement) {
+		listings = append(listings, Listing{URL: e.Request.AbsoluteURL(e.Attr("href"))})
+	})
+
+	err = c.Visit(vacantPositionsUrl)
+
+	return listings, err
+}
+
+// Get the details of position
+func (source) Detail(ctx context.Context, listing Listing) (Position, error) {
+	position := listing.Position()
+
+	c := tea.NewCollector(ctx)
+
+	c.OnHTML("div.container.positions.nocontent h1", func(e *colly.HTMLElement) {
+		position.Title = strings.TrimSpace(e.Text)
+
+	})
+
+	c.OnHTML("div.introductory-note p:last-child", func(e *colly.HTMLElement) {
+		position.Date = strings.TrimSpace(e.Text)
+	})
+
+	c.OnHTML("div.container.positions.nocontent", func(e *colly.HTMLElement) {
+
+		position.Description += fmt.Sprintln(strings.TrimSpace(strings.ToValidUTF8(e.Text, "")))
+		position.Description = strings.ReplaceAll(position.Description, position.Title, "")
+		position.Description = strings.TrimSpace(strings.ReplaceAll(position.Description, e.ChildText("div.introductory-note"), ""))
+	})
+
+	err := c.Visit(position.URL)
+
+	return position, err
+
+}
+
+func init() {
+	tea.Register(source{}, tea.Location{City: "Uppsala", Country: "Sweden", Language: "en"})
+}
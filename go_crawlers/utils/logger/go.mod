@@ -1,3 +1,3 @@
 module fenjan.ai-hue.ir/logger
 
-go 1.18
+go 1.21
@@ -1,20 +1,37 @@
-// Source: https://stackoverflow.com/questions/19965795/how-to-write-log-to-file
-
+// Package logger sets up the structured logging of the crawlers on log/slog.
+//
+// The records are written to stdout and to a log file that is rotated when it grows too big,
+// as text or as JSON. The format, level and file are read from the LOG_FORMAT, LOG_LEVEL,
+// LOG_FILE, LOG_MAX_SIZE_MB and LOG_MAX_BACKUPS environment variables, and the commands can
+// override them with flags. The standard log package writes to the same records at the info level.
+//
+// The records only go to stdout until a command calls Setup, so the packages and tests that import
+// the logger don't open the log file.
 package logger
 
 import (
+	"context"
 	"flag"
+	"fmt"
 	"io"
-	"log"
+	"log/slog"
 	"os"
 	"path/filepath"
 	"runtime"
+	"strconv"
+	"strings"
+	"sync"
 )
 
-var (
-	Error *log.Logger
+// The keys of the fields shared by the records, they must not change so the logs can be parsed
+const (
+	SourceKey  = "source"
+	URLKey     = "url"
+	StatusKey  = "status"
+	AttemptKey = "attempt"
+	KindKey    = "kind"
+	ErrorKey   = "error"
 )
-var err error
 
 var (
 	// Get current file full path from runtime
@@ -23,37 +40,154 @@ var (
 	ProjectRootPath = filepath.Join(filepath.Dir(b), "../../../")
 )
 
+// Config selects the format, the level and the file of the logs
+type Config struct {
+	// Format is "text" or "json"
+	Format string
+	// Level is "debug", "info", "warn" or "error"
+	Level string
+	// File is the path of the log file, relative paths are relative to the project root,
+	// an empty path only logs to stdout
+	File string
+	// MaxSize is the size in megabytes the log file is rotated at
+	MaxSize int
+	// MaxBackups is the number of rotated log files that are kept
+	MaxBackups int
+}
+
+// ConfigFromEnv returns the config set by the LOG_* environment variables, with the defaults of
+// text records at the info level, written to log/crawlers.log that is rotated at 10 MB keeping 5 files
+func ConfigFromEnv() Config {
+	config := Config{Format: "text", Level: "info", File: "log/crawlers.log", MaxSize: 10, MaxBackups: 5}
+	if format, ok := os.LookupEnv("LOG_FORMAT"); ok {
+		config.Format = format
+	}
+	if level, ok := os.LookupEnv("LOG_LEVEL"); ok {
+		config.Level = level
+	}
+	if file, ok := os.LookupEnv("LOG_FILE"); ok {
+		config.File = file
+	}
+	if size, err := strconv.Atoi(os.Getenv("LOG_MAX_SIZE_MB")); err == nil {
+		config.MaxSize = size
+	}
+	if backups, err := strconv.Atoi(os.Getenv("LOG_MAX_BACKUPS")); err == nil {
+		config.MaxBackups = backups
+	}
+	return config
+}
+
+// RegisterFlags adds the --log-format, --log-level and --log-file flags to the flag set,
+// their defaults are the current values of the config
+func (c *Config) RegisterFlags(flags *flag.FlagSet) {
+	flags.StringVar(&c.Format, "log-format", c.Format, "format of the logs, text or json")
+	flags.StringVar(&c.Level, "log-level", c.Level, "minimum level of the logs, debug, info, warn or error")
+	flags.StringVar(&c.File, "log-file", c.File, "file the logs are also written to, empty to only log to stdout")
+}
+
+var (
+	setupMu sync.Mutex
+	// file is the log file of the current config, it is closed when the config changes
+	file *rotatingFile
+	// output and level are shared by the handlers of every config, Setup swaps the file behind output
+	// and sets level, so the loggers made before, like the ones of ForSource, follow the new config
+	// instead of writing to a closed file
+	output = &switchWriter{writer: os.Stdout}
+	level  = new(slog.LevelVar)
+)
+
+// switchWriter is a writer whose underlying writer can be swapped while it is used
+type switchWriter struct {
+	mu     sync.Mutex
+	writer io.Writer
+}
+
+func (w *switchWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.writer.Write(p)
+}
+
+func (w *switchWriter) swap(writer io.Writer) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.writer = writer
+}
+
 func init() {
+	slog.SetDefault(slog.New(slog.NewTextHandler(output, &slog.HandlerOptions{Level: level})))
+}
+
+// Setup makes the logger of the config the default slog logger, which is also used by the log package
+func Setup(config Config) error {
+	var newLevel slog.Level
+	if err := newLevel.UnmarshalText([]byte(config.Level)); err != nil {
+		return fmt.Errorf("invalid log level %q: %w", config.Level, err)
+	}
+
+	options := &slog.HandlerOptions{Level: level}
+	var handler slog.Handler
+	switch strings.ToLower(config.Format) {
+	case "", "text":
+		handler = slog.NewTextHandler(output, options)
+	case "json":
+		handler = slog.NewJSONHandler(output, options)
+	default:
+		return fmt.Errorf("invalid log format %q, use text or json", config.Format)
+	}
+
+	setupMu.Lock()
+	defer setupMu.Unlock()
+
+	var writer io.Writer = os.Stdout
+	var newFile *rotatingFile
+	if config.File != "" {
+		path := config.File
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(ProjectRootPath, path)
+		}
+		var err error
+		newFile, err = openRotatingFile(path, int64(config.MaxSize)*1024*1024, config.MaxBackups)
+		if err != nil {
+			return err
+		}
+		writer = io.MultiWriter(os.Stdout, newFile)
+	}
+
+	// The old file is closed once no logger can write to it anymore
+	output.swap(writer)
+	level.Set(newLevel)
+	slog.SetDefault(slog.New(handler))
+	if file != nil {
+		file.Close()
+	}
+	file = newFile
+	return nil
+}
+
+// ForSource returns a child of the default logger whose records have the source field
+func ForSource(source string) *slog.Logger {
+	return slog.Default().With(SourceKey, source)
+}
+
+// contextKey is the key of the logger in a context
+type contextKey struct{}
+
+// NewContext returns a context that carries the logger, e.g. the logger of the source being crawled
+func NewContext(ctx context.Context, logger *slog.Logger) context.Context {
+	return context.WithValue(ctx, contextKey{}, logger)
+}
+
+// FromContext returns the logger carried by the context, or the default logger
+func FromContext(ctx context.Context) *slog.Logger {
+	if logger, ok := ctx.Value(contextKey{}).(*slog.Logger); ok {
+		return logger
+	}
+	return slog.Default()
+}
 
-	// Set location of log file
-	var logPath = filepath.Join(ProjectRootPath, "log/scrapers_fatal_errors.log")
-	err = os.MkdirAll(filepath.Dir(logPath), os.ModePerm)
-	if err != nil {
-		panic(err)
-	}
-	// Create log file if not exist, else open it
-	flag.Parse()
-	var file, err = os.OpenFile(logPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, os.ModePerm)
-	if err != nil {
-		panic(err)
-	}
-
-	// Create multi-writer to log to both file and terminal
-	multiWriter := io.MultiWriter(file, os.Stdout)
-
-	// // Get the name of package
-	// var programPath string
-	// ex, err := os.Executable()
-	// if err != nil {
-	// 	panic(err)
-	// }
-	// exePath := filepath.Dir(ex)
-	// fmt.Println(exePath)
-	// if filepath.Base(exePath) == "exe" {
-	// 	programPath = strings.Split(exePath, "/")[len(strings.Split(exePath, "/"))-4]
-	// } else {
-	// 	programPath = filepath.Base(exePath)
-	// }
-	// Error = log.New(multiWriter, "Package "+programPath+"🦂 ", log.Ldate|log.Ltime)
-	Error = log.New(multiWriter, "", log.Ldate|log.Ltime)
+// Failure records a failed crawl or request at the error level with the url, kind and error fields,
+// the logger is usually the child logger of the source so the record has the source field too
+func Failure(logger *slog.Logger, url, kind string, cause error) {
+	logger.Error("Crawling failed ☠️!", URLKey, url, KindKey, kind, ErrorKey, cause)
 }
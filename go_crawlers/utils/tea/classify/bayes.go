@@ -0,0 +1,173 @@
+package classify
+
+import (
+	"bufio"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"unicode"
+)
+
+// trainingData are the labeled examples of the default model, one per line:
+// label <TAB> title [<TAB> description], lines starting with # are comments
+//
+//go:embed training.tsv
+var trainingData string
+
+// Example is a labeled position to train the model with
+type Example struct {
+	Label       Label
+	Title       string
+	Description string
+}
+
+// ReadExamples reads the examples of a TSV file in the format of training.tsv
+func ReadExamples(r io.Reader) ([]Example, error) {
+	var examples []Example
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		fields := strings.Split(text, "\t")
+		label, ok := ParseLabel(fields[0])
+		if !ok || len(fields) < 2 {
+			return nil, fmt.Errorf("line %d: want a label of %v and a title separated by a tab", line, Labels)
+		}
+		example := Example{Label: label, Title: fields[1]}
+		if len(fields) > 2 {
+			example.Description = fields[2]
+		}
+		examples = append(examples, example)
+	}
+	return examples, scanner.Err()
+}
+
+// Model is a multinomial naive Bayes model of the words of the positions, the words of the title
+// count titleWeight times. It is saved and loaded as JSON.
+type Model struct {
+	// Documents counts the examples of each label
+	Documents map[Label]int `json:"documents"`
+	// Words counts the occurrences of each word in the examples of each label
+	Words map[Label]map[string]float64 `json:"words"`
+	// Totals are the sums of Words of each label
+	Totals map[Label]float64 `json:"totals"`
+	// Vocabulary is the number of different words in all examples
+	Vocabulary int `json:"vocabulary"`
+}
+
+// Train returns the model of the examples
+func Train(examples []Example) *Model {
+	m := &Model{Documents: map[Label]int{}, Words: map[Label]map[string]float64{}, Totals: map[Label]float64{}}
+	vocabulary := map[string]bool{}
+	for _, example := range examples {
+		m.Documents[example.Label]++
+		if m.Words[example.Label] == nil {
+			m.Words[example.Label] = map[string]float64{}
+		}
+		for word, count := range features(example.Title, example.Description) {
+			m.Words[example.Label][word] += count
+			m.Totals[example.Label] += count
+			vocabulary[word] = true
+		}
+	}
+	m.Vocabulary = len(vocabulary)
+	return m
+}
+
+// LogPosterior returns the normalized log probability of each label of a position, and whether
+// any of its words is known to the model, when none is the probabilities are the priors
+func (m *Model) LogPosterior(title, description string) (map[Label]float64, bool) {
+	var documents int
+	for _, count := range m.Documents {
+		documents += count
+	}
+	if documents == 0 {
+		return nil, false
+	}
+
+	words := features(title, description)
+	known := false
+	logProbabilities := map[Label]float64{}
+	for _, label := range Labels {
+		// Laplace smoothing, so a label that was never seen with a word isn't ruled out by it
+		logProbability := math.Log(float64(m.Documents[label]+1) / float64(documents+len(Labels)))
+		denominator := m.Totals[label] + float64(m.Vocabulary) + 1
+		for word, count := range words {
+			seen := m.Words[label][word]
+			if seen > 0 {
+				known = true
+			}
+			logProbability += count * math.Log((seen+1)/denominator)
+		}
+		logProbabilities[label] = logProbability
+	}
+
+	// Normalize, log(sum(exp)) computed around the maximum to not underflow
+	max := math.Inf(-1)
+	for _, value := range logProbabilities {
+		max = math.Max(max, value)
+	}
+	var sum float64
+	for _, value := range logProbabilities {
+		sum += math.Exp(value - max)
+	}
+	for label := range logProbabilities {
+		logProbabilities[label] -= max + math.Log(sum)
+	}
+	return logProbabilities, known
+}
+
+// Save writes the model as JSON
+func (m *Model) Save(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(m)
+}
+
+// LoadModel reads a model saved by Save
+func LoadModel(r io.Reader) (*Model, error) {
+	var m Model
+	if err := json.NewDecoder(r).Decode(&m); err != nil {
+		return nil, fmt.Errorf("invalid model: %w", err)
+	}
+	if len(m.Documents) == 0 {
+		return nil, fmt.Errorf("invalid model: it has no examples")
+	}
+	return &m, nil
+}
+
+// features counts the words of the title and description, the description is capped so a long one
+// doesn't drown the title
+func features(title, description string) map[string]float64 {
+	const maxDescriptionWords = 200
+	counts := map[string]float64{}
+	for _, word := range Tokenize(title) {
+		counts[word] += titleWeight
+	}
+	for i, word := range Tokenize(description) {
+		if i == maxDescriptionWords {
+			break
+		}
+		counts[word]++
+	}
+	return counts
+}
+
+// Tokenize returns the lower case words of the text with at least 2 letters or digits
+func Tokenize(text string) []string {
+	var words []string
+	for _, word := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		if len([]rune(word)) >= 2 {
+			words = append(words, word)
+		}
+	}
+	return words
+}
@@ -0,0 +1,251 @@
+// Package classify tells the PhD positions apart from the postdoc, faculty, staff and other jobs that
+// many university websites list together, e.g. "Doktorand (m/w/d) in Robotik", "Postdoc in Ecology",
+// "Universitetslektor i matematik" or "Laboratory technician".
+//
+// A position is scored by weighted phrases in English, German, Swedish, Finnish and Dutch found in its
+// title and description, combined with a naive Bayes model of the words of labeled examples. The default
+// model is trained from the examples embedded from training.tsv, and can be retrained offline from more
+// examples with Train and saved as JSON.
+package classify
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Label is the kind of a position
+type Label string
+
+const (
+	PhD     Label = "phd"
+	Postdoc Label = "postdoc"
+	Faculty Label = "faculty"
+	Staff   Label = "staff"
+	Other   Label = "other"
+)
+
+// Labels are all the labels, in the order ties are broken
+var Labels = []Label{PhD, Postdoc, Faculty, Staff, Other}
+
+// ParseLabel returns the label of its name, false if there is no such label
+func ParseLabel(name string) (Label, bool) {
+	for _, label := range Labels {
+		if string(label) == strings.ToLower(strings.TrimSpace(name)) {
+			return label, true
+		}
+	}
+	return "", false
+}
+
+// Phrase is evidence of a label, Weight is added to the log odds of the label for every
+// occurrence in the description, and titleWeight times it for the occurrences in the title
+type Phrase struct {
+	Text   string
+	Label  Label
+	Weight float64
+}
+
+// titleWeight is how much more a phrase in the title counts than one in the description
+const titleWeight = 2.0
+
+// maxOccurrences limits how many occurrences of a phrase in the description count, long descriptions
+// mention "PhD" in the requirements of postdoc positions too
+const maxOccurrences = 2
+
+// DefaultPhrases are matched case-insensitively anywhere in the text, so they also match inside compound words,
+// e.g. "doktorand" in "doktorandtjänst". When phrases overlap only the leftmost-longest counts, so
+// "postdoctoral" isn't also taken for "doctoral".
+var DefaultPhrases = []Phrase{
+	// PhD positions
+	{"phd position", PhD, 2}, {"phd student", PhD, 2}, {"phd candidate", PhD, 2}, {"phd researcher", PhD, 2},
+	{"phd", PhD, 1.5}, {"ph.d.", PhD, 1.5}, {"doctoral candidate", PhD, 2}, {"doctoral student", PhD, 2},
+	{"doctoral researcher", PhD, 2}, {"doctoral position", PhD, 2}, {"doctoral", PhD, 1},
+	{"early stage researcher", PhD, 1.5}, {"junior researcher", PhD, 1},
+	{"doktorand", PhD, 2}, {"promotionsstelle", PhD, 2}, {"promotion", PhD, 1}, // German, and Swedish "doktorand"
+	{"forskarstuderande", PhD, 2}, {"doktorandtjänst", PhD, 2}, // Swedish
+	{"väitöskirjatutkija", PhD, 2}, {"tohtorikoulutettava", PhD, 2}, {"tohtoriopiskelija", PhD, 2}, // Finnish
+	{"promovendus", PhD, 2}, {"promotieplaats", PhD, 2}, {"promotieonderzoek", PhD, 2}, // Dutch
+
+	// Postdoc positions
+	{"postdoc", Postdoc, 2.5}, {"post-doc", Postdoc, 2.5}, {"postdoctoral", Postdoc, 2.5}, {"post-doctoral", Postdoc, 2.5},
+	{"research fellow", Postdoc, 1}, {"postdoktorand", Postdoc, 2.5}, {"postdoktor", Postdoc, 2.5},
+	{"tutkijatohtori", Postdoc, 2.5},
+
+	// Faculty positions
+	{"professor", Faculty, 2.5}, {"professur", Faculty, 2.5}, {"tenure track", Faculty, 2}, {"tenure-track", Faculty, 2},
+	{"lecturer", Faculty, 2}, {"universitetslektor", Faculty, 2.5}, {"lektor", Faculty, 1.5}, {"docent", Faculty, 2},
+	{"dozent", Faculty, 2}, {"yliopistonlehtori", Faculty, 2.5}, {"hoogleraar", Faculty, 2.5}, {"universitair docent", Faculty, 2.5},
+	{"faculty", Faculty, 1},
+
+	// Technical and administrative staff
+	{"technician", Staff, 2}, {"engineer", Staff, 1.5}, {"administrator", Staff, 2}, {"coordinator", Staff, 2},
+	{"secretary", Staff, 2}, {"manager", Staff, 1.5}, {"officer", Staff, 1.5}, {"librarian", Staff, 2},
+	{"controller", Staff, 1.5}, {"it support", Staff, 2}, {"specialist", Staff, 1},
+	{"techniker", Staff, 2}, {"sachbearbeit", Staff, 2}, {"verwaltung", Staff, 1.5}, {"sekretär", Staff, 2},
+	{"ingenjör", Staff, 2}, {"tekniker", Staff, 2}, {"handläggare", Staff, 2}, {"administratör", Staff, 2},
+	{"insinööri", Staff, 2}, {"koordinaattori", Staff, 2}, {"suunnittelija", Staff, 1.5}, {"sihteeri", Staff, 2},
+	{"beleidsmedewerker", Staff, 2}, {"studieadviseur", Staff, 2},
+
+	// Student jobs, internships and the like
+	{"student assistant", Other, 2}, {"studentische hilfskraft", Other, 2.5}, {"hilfskraft", Other, 2},
+	{"internship", Other, 2}, {"praktikum", Other, 2}, {"trainee", Other, 1.5},
+	{"ausbildung", Other, 2}, {"apprentice", Other, 2}, {"master's thesis", Other, 2}, {"master thesis", Other, 2},
+	{"masterarbeit", Other, 2}, {"amanuens", Other, 2}, {"harjoittelija", Other, 2}, {"stagiair", Other, 2},
+}
+
+// Result is the label of a position and the probability of the label, rounded to 4 decimals
+// so it reads well in the database and the golden files
+type Result struct {
+	Label      Label   `json:"label"`
+	Confidence float64 `json:"confidence"`
+}
+
+// Classifier scores the positions with its phrases and naive Bayes model
+type Classifier struct {
+	Phrases []Phrase
+	Model   *Model
+}
+
+// New returns a classifier with the default phrases and the model
+func New(model *Model) *Classifier {
+	return &Classifier{Phrases: DefaultPhrases, Model: model}
+}
+
+var (
+	defaultOnce       sync.Once
+	defaultClassifier *Classifier
+)
+
+// Default returns the classifier with the default phrases and the model trained from training.tsv
+func Default() *Classifier {
+	defaultOnce.Do(func() {
+		examples, err := ReadExamples(strings.NewReader(trainingData))
+		if err != nil {
+			panic("classify: invalid training.tsv: " + err.Error())
+		}
+		defaultClassifier = New(Train(examples))
+	})
+	return defaultClassifier
+}
+
+// Classify returns the most probable label of a position with the title and description,
+// a position without any evidence of a label is Other with no confidence
+func (c *Classifier) Classify(title, description string) Result {
+	scores := c.Scores(title, description)
+	if scores == nil {
+		return Result{Label: Other}
+	}
+	best := Result{Label: Other}
+	for _, label := range Labels {
+		if scores[label] > best.Confidence {
+			best = Result{Label: label, Confidence: scores[label]}
+		}
+	}
+	best.Confidence = math.Round(best.Confidence*1e4) / 1e4
+	return best
+}
+
+// Scores returns the probability of each label of a position, nil if the phrases and the model
+// know nothing about the title and description
+func (c *Classifier) Scores(title, description string) map[Label]float64 {
+	logOdds, matched := c.phraseScores(title, description)
+	known := false
+	if c.Model != nil {
+		posterior, ok := c.Model.LogPosterior(title, description)
+		known = ok
+		for label, value := range posterior {
+			logOdds[label] += value
+		}
+	}
+	if !matched && !known {
+		return nil
+	}
+	return softmax(logOdds)
+}
+
+// phraseScores sums the weights of the phrases found in the title and description by label
+func (c *Classifier) phraseScores(title, description string) (map[Label]float64, bool) {
+	scores := map[Label]float64{}
+	matched := false
+	for _, field := range []struct {
+		text        string
+		weight      float64
+		occurrences int
+	}{{title, titleWeight, math.MaxInt}, {description, 1, maxOccurrences}} {
+		for _, phrase := range c.match(strings.ToLower(field.text), field.occurrences) {
+			scores[phrase.Label] += phrase.Weight * field.weight
+			matched = true
+		}
+	}
+	return scores, matched
+}
+
+// match returns the phrase of each occurrence in the lower case text, keeping only the leftmost-longest of
+// overlapping occurrences and at most max occurrences of each phrase
+func (c *Classifier) match(text string, max int) []Phrase {
+	type occurrence struct {
+		start, end int
+		phrase     Phrase
+	}
+	var occurrences []occurrence
+	for _, phrase := range c.Phrases {
+		needle := strings.ToLower(phrase.Text)
+		for offset := 0; ; {
+			i := strings.Index(text[offset:], needle)
+			if i < 0 {
+				break
+			}
+			start := offset + i
+			occurrences = append(occurrences, occurrence{start, start + len(needle), phrase})
+			offset = start + len(needle)
+		}
+	}
+	// The leftmost occurrences win, and the longest of those starting together, so "postdoctoral researcher"
+	// is "postdoctoral" and not "doctoral researcher"
+	sort.SliceStable(occurrences, func(i, j int) bool {
+		if occurrences[i].start != occurrences[j].start {
+			return occurrences[i].start < occurrences[j].start
+		}
+		return occurrences[i].end > occurrences[j].end
+	})
+
+	var kept []occurrence
+	var phrases []Phrase
+	counts := map[string]int{}
+	for _, o := range occurrences {
+		overlaps := false
+		for _, k := range kept {
+			if o.start < k.end && k.start < o.end {
+				overlaps = true
+				break
+			}
+		}
+		if overlaps || counts[o.phrase.Text] >= max {
+			continue
+		}
+		kept = append(kept, o)
+		counts[o.phrase.Text]++
+		phrases = append(phrases, o.phrase)
+	}
+	return phrases
+}
+
+// softmax turns the log odds of the labels into probabilities
+func softmax(logOdds map[Label]float64) map[Label]float64 {
+	max := math.Inf(-1)
+	for _, label := range Labels {
+		max = math.Max(max, logOdds[label])
+	}
+	var sum float64
+	probabilities := map[Label]float64{}
+	for _, label := range Labels {
+		probabilities[label] = math.Exp(logOdds[label] - max)
+		sum += probabilities[label]
+	}
+	for label := range probabilities {
+		probabilities[label] /= sum
+	}
+	return probabilities
+}
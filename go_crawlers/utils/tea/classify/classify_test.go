@@ -0,0 +1,76 @@
+package classify_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"fenjan.ai-hue.ir/tea/classify"
+)
+
+func TestClassifyMultilingualTitles(t *testing.T) {
+	tests := []struct {
+		title, description string
+		want               classify.Label
+	}{
+		{"Doktorand (m/w/d) in Robotik", "", classify.PhD},
+		{"Väitöskirjatutkija, konenäkö", "", classify.PhD},
+		{"Doktorand i reglerteknik", "", classify.PhD},
+		{"Promovendus Quantum Optics", "", classify.PhD},
+		{"Doctoral candidate in Urban Planning", "", classify.PhD},
+		{"Wissenschaftliche:r Mitarbeiter:in", "Die Stelle bietet die Möglichkeit zur Promotion.", classify.PhD},
+		{"Postdoctoral researcher in Astrophysics", "Applicants must hold a PhD in physics.", classify.Postdoc},
+		{"Tutkijatohtori, biologia", "", classify.Postdoc},
+		{"Universitetslektor i statistik", "", classify.Faculty},
+		{"Assistant Professor in Chemistry", "Candidates should hold a PhD.", classify.Faculty},
+		{"Laboratory technician", "", classify.Staff},
+		{"Sachbearbeiter*in Drittmittel", "", classify.Staff},
+		{"Studentische Hilfskraft", "", classify.Other},
+		{"Internship in Software Development", "", classify.Other},
+	}
+	classifier := classify.Default()
+	for _, test := range tests {
+		got := classifier.Classify(test.title, test.description)
+		if got.Label != test.want {
+			t.Errorf("Classify(%q) = %s (%.2f), want %s", test.title, got.Label, got.Confidence, test.want)
+		}
+		if got.Confidence <= 0.5 || got.Confidence > 1 {
+			t.Errorf("Classify(%q) confidence = %.2f, want more than 0.5", test.title, got.Confidence)
+		}
+	}
+}
+
+func TestClassifyWithoutEvidence(t *testing.T) {
+	got := classify.Default().Classify("", "")
+	if got != (classify.Result{Label: classify.Other}) {
+		t.Errorf("Classify of an empty position = %+v, want other with no confidence", got)
+	}
+}
+
+func TestTrainSaveLoad(t *testing.T) {
+	examples, err := classify.ReadExamples(strings.NewReader(
+		"# comment\n\nphd\tDoctoral student in biology\npostdoc\tPostdoc in biology\tA PhD is required\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(examples) != 2 {
+		t.Fatalf("got %d examples, want 2", len(examples))
+	}
+
+	var saved bytes.Buffer
+	if err := classify.Train(examples).Save(&saved); err != nil {
+		t.Fatal(err)
+	}
+	model, err := classify.LoadModel(&saved)
+	if err != nil {
+		t.Fatal(err)
+	}
+	classifier := &classify.Classifier{Model: model}
+	if got := classifier.Classify("Student in biology", "").Label; got != classify.PhD {
+		t.Errorf("loaded model labels a student %s, want phd", got)
+	}
+
+	if _, err := classify.ReadExamples(strings.NewReader("lecturer\tLecturer in biology\n")); err == nil {
+		t.Error("ReadExamples accepted an unknown label")
+	}
+}
@@ -0,0 +1,524 @@
+package tea
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"fenjan.ai-hue.ir/logger"
+	"fenjan.ai-hue.ir/tea/metrics"
+	"github.com/gocolly/colly"
+)
+
+// Keys used to keep the state of a visit in the colly context shared by its retries
+const (
+	attemptKey = "tea.attempt"
+	failureKey = "tea.failure"
+)
+
+// collectorConfig holds the retry, timeout and rate limit policy of a Collector
+type collectorConfig struct {
+	retries      int
+	timeout      time.Duration
+	backoff      time.Duration
+	maxBackoff   time.Duration
+	tooManyWait  time.Duration
+	parallelism  int
+	delay        time.Duration
+	randomDelay  time.Duration
+	collyOptions []func(*colly.Collector)
+}
+
+// CollectorOption changes the default policy of a Collector
+type CollectorOption func(*collectorConfig)
+
+// WithRetries sets the number of times a failed request is retried before giving up
+func WithRetries(retries int) CollectorOption {
+	return func(config *collectorConfig) {
+		config.retries = retries
+	}
+}
+
+// WithTimeout sets the timeout of each request.
+// Like WithRateLimit, it has no effect on the collectors created during a Crawl, which share
+// the HTTP client of the crawl, a source sets it for the whole crawl with CollectorOptions.
+func WithTimeout(timeout time.Duration) CollectorOption {
+	return func(config *collectorConfig) {
+		config.timeout = timeout
+	}
+}
+
+// WithBackoff sets the wait before the first retry and the maximum wait between retries,
+// the wait doubles after every failed attempt and a random jitter is applied to it
+func WithBackoff(backoff, maxBackoff time.Duration) CollectorOption {
+	return func(config *collectorConfig) {
+		config.backoff = backoff
+		config.maxBackoff = maxBackoff
+	}
+}
+
+// WithTooManyRequestsWait sets the wait before the first retry of a 429 Too Many Requests response
+// that has no Retry-After header
+func WithTooManyRequestsWait(wait time.Duration) CollectorOption {
+	return func(config *collectorConfig) {
+		config.tooManyWait = wait
+	}
+}
+
+// WithRateLimit sets the maximum number of concurrent requests to each domain, and the delay
+// plus a random part of randomDelay to wait before each request
+func WithRateLimit(parallelism int, delay, randomDelay time.Duration) CollectorOption {
+	return func(config *collectorConfig) {
+		config.parallelism = parallelism
+		config.delay = delay
+		config.randomDelay = randomDelay
+	}
+}
+
+// WithCollyOptions passes options like colly.AllowedDomains to the underlying colly collector
+func WithCollyOptions(options ...func(*colly.Collector)) CollectorOption {
+	return func(config *collectorConfig) {
+		config.collyOptions = append(config.collyOptions, options...)
+	}
+}
+
+// Collector is a colly collector with the logging, retry, timeout and rate limit policy shared by all crawlers.
+// Its requests are made concurrently, but its callbacks are called one at a time,
+// so they can change the variables of the crawler without locking.
+type Collector struct {
+	*colly.Collector
+	ctx    context.Context
+	config collectorConfig
+	// log is the logger of the source being crawled, taken from ctx
+	log *slog.Logger
+	// source is the table name of the source being crawled, the label of its request metrics
+	source string
+
+	// callbacks is held while a callback of the collector is running
+	callbacks sync.Mutex
+	// visiting is the number of Visit calls waiting for their responses
+	visiting int32
+	// nested keeps the contexts of the visits made from the callbacks of another visit
+	nestedMu sync.Mutex
+	nested   []*colly.Context
+}
+
+// sharedCollectorKey is the key of the collector shared by all requests of a crawl in a context
+type sharedCollectorKey struct{}
+
+// HTTPTransport makes the HTTP requests of the collectors and of HTTPClient,
+// the tests replace it to replay recorded responses instead of visiting the websites
+var HTTPTransport http.RoundTripper = http.DefaultTransport
+
+// HTTPClient returns a client for the sources that make their requests without a Collector.
+// Its requests have the timeout and retry policy of the collectors of the crawl in ctx, or of
+// NewCollector with the options, and they are counted in the request metrics of the source.
+func HTTPClient(ctx context.Context, options ...CollectorOption) *http.Client {
+	source, _ := ctx.Value(sourceNameKey{}).(string)
+	return &http.Client{Transport: &retryTransport{
+		config: collectorConfigOf(ctx, options), log: logger.FromContext(ctx), source: source, base: HTTPTransport,
+	}}
+}
+
+// sourceNameKey is the key of the table name of the source being crawled in a context
+type sourceNameKey struct{}
+
+// withSharedCollector returns a context that makes NewCollector clone c
+func withSharedCollector(ctx context.Context, c *Collector) context.Context {
+	return context.WithValue(ctx, sharedCollectorKey{}, c)
+}
+
+// NewCollector creates a Collector, by default each request has a timeout of 60 seconds,
+// failed requests are retried 5 times with exponential backoff, and at most 4 requests are made
+// to a domain at the same time with a delay of 0.25 to 0.5 seconds.
+// When ctx is done the requests in flight are canceled and no new request or retry is made.
+//
+// During a Crawl, the collector is a clone of the collector of the crawl, so the rate limits,
+// the HTTP client and the visited URLs are shared by all the requests made to the source.
+func NewCollector(ctx context.Context, options ...CollectorOption) *Collector {
+	shared, _ := ctx.Value(sharedCollectorKey{}).(*Collector)
+	config := collectorConfigOf(ctx, options)
+
+	source, _ := ctx.Value(sourceNameKey{}).(string)
+	c := &Collector{ctx: ctx, config: config, log: logger.FromContext(ctx), source: source}
+	if shared != nil {
+		c.Collector = shared.Collector.Clone()
+		for _, option := range config.collyOptions {
+			option(c.Collector)
+		}
+	} else {
+		c.Collector = colly.NewCollector(append([]func(*colly.Collector){colly.Async(true)}, config.collyOptions...)...)
+		c.SetRequestTimeout(config.timeout)
+		c.WithTransport(&contextTransport{ctx: ctx, base: HTTPTransport})
+		c.Limit(&colly.LimitRule{
+			DomainGlob:  "*",
+			Parallelism: config.parallelism,
+			Delay:       config.delay,
+			RandomDelay: config.randomDelay,
+		})
+	}
+
+	// Add the OnRequest function to log the URLs that have visited
+	c.Collector.OnRequest(func(r *colly.Request) {
+		if ctx.Err() != nil {
+			r.Abort()
+			return
+		}
+		c.log.Debug("Visiting 🥷", logger.URLKey, r.URL.String())
+	})
+
+	// Count the responses, the failed requests are counted by the error handler
+	c.Collector.OnResponse(func(r *colly.Response) {
+		metrics.ObserveRequest(c.source, r.StatusCode)
+	})
+
+	// Set error handler
+	c.Collector.OnError(c.handleError)
+
+	return c
+}
+
+// collectorConfigOf returns the policy of the collector of the crawl in ctx, or the default policy,
+// changed by the options
+func collectorConfigOf(ctx context.Context, options []CollectorOption) collectorConfig {
+	config := collectorConfig{
+		retries:     5,
+		timeout:     60 * time.Second,
+		backoff:     2 * time.Second,
+		maxBackoff:  5 * time.Minute,
+		tooManyWait: 30 * time.Second,
+		parallelism: 4,
+		delay:       250 * time.Millisecond,
+		randomDelay: 250 * time.Millisecond,
+	}
+	if shared, ok := ctx.Value(sharedCollectorKey{}).(*Collector); ok {
+		config = shared.config
+		config.collyOptions = nil
+	}
+	for _, option := range options {
+		option(&config)
+	}
+	return config
+}
+
+// OnHTML registers a function that is called on every HTML element matched by the selector
+func (c *Collector) OnHTML(selector string, f colly.HTMLCallback) {
+	c.Collector.OnHTML(selector, func(e *colly.HTMLElement) {
+		c.callbacks.Lock()
+		defer c.callbacks.Unlock()
+		f(e)
+	})
+}
+
+// OnXML registers a function that is called on every XML element matched by the xpath query
+func (c *Collector) OnXML(query string, f colly.XMLCallback) {
+	c.Collector.OnXML(query, func(e *colly.XMLElement) {
+		c.callbacks.Lock()
+		defer c.callbacks.Unlock()
+		f(e)
+	})
+}
+
+// OnResponse registers a function that is called on every response
+func (c *Collector) OnResponse(f colly.ResponseCallback) {
+	c.Collector.OnResponse(func(r *colly.Response) {
+		c.callbacks.Lock()
+		defer c.callbacks.Unlock()
+		f(r)
+	})
+}
+
+// OnScraped registers a function that is called after the OnHTML callbacks of every response
+func (c *Collector) OnScraped(f colly.ScrapedCallback) {
+	c.Collector.OnScraped(func(r *colly.Response) {
+		c.callbacks.Lock()
+		defer c.callbacks.Unlock()
+		f(r)
+	})
+}
+
+// Visit requests the URL and returns an error if the request still fails after all retries,
+// or if the context of the collector is done.
+// When it is called from a callback, e.g. to visit the next page, it returns without waiting
+// and the error of the request is returned by the Visit that called the callback.
+func (c *Collector) Visit(url string) error {
+	return c.VisitAll(url)
+}
+
+// VisitAll requests the URLs concurrently and returns the first error of Visit.
+// Like Visit, it must not be called from more than one goroutine at a time.
+func (c *Collector) VisitAll(urls ...string) error {
+	if err := c.ctx.Err(); err != nil {
+		return err
+	}
+
+	if atomic.LoadInt32(&c.visiting) > 0 {
+		// Called from a callback, the running Visit waits for these requests
+		var firstErr error
+		for _, url := range urls {
+			ctx := colly.NewContext()
+			if err := c.Collector.Request(http.MethodGet, url, nil, ctx, nil); err != nil && firstErr == nil {
+				firstErr = err
+			}
+			c.nestedMu.Lock()
+			c.nested = append(c.nested, ctx)
+			c.nestedMu.Unlock()
+		}
+		return firstErr
+	}
+
+	atomic.AddInt32(&c.visiting, 1)
+	defer atomic.AddInt32(&c.visiting, -1)
+
+	ctxs := make([]*colly.Context, len(urls))
+	requestErrs := make([]error, len(urls))
+	for i, url := range urls {
+		ctxs[i] = colly.NewContext()
+		requestErrs[i] = c.Collector.Request(http.MethodGet, url, nil, ctxs[i], nil)
+	}
+	c.Wait()
+
+	for i := range urls {
+		if err := c.visitError(ctxs[i], requestErrs[i]); err != nil {
+			return err
+		}
+	}
+
+	c.nestedMu.Lock()
+	nested := c.nested
+	c.nested = nil
+	c.nestedMu.Unlock()
+	for _, ctx := range nested {
+		if failure, ok := ctx.GetAny(failureKey).(error); ok {
+			return failure
+		}
+	}
+	return nil
+}
+
+// visitError returns the error of a visit after all its retries are done
+func (c *Collector) visitError(ctx *colly.Context, requestErr error) error {
+	if failure, ok := ctx.GetAny(failureKey).(error); ok {
+		return failure
+	}
+	// The first attempt failed but one of the retries succeeded
+	if _, retried := ctx.GetAny(attemptKey).(int); retried {
+		return nil
+	}
+	// A request aborted because the context is done doesn't return an error
+	if requestErr == nil {
+		requestErr = c.ctx.Err()
+	}
+	return requestErr
+}
+
+// handleError retries the failed request after a backoff, or records the failure when it can't be retried anymore
+func (c *Collector) handleError(r *colly.Response, err error) {
+	metrics.ObserveRequest(c.source, r.StatusCode)
+
+	attempt, _ := r.Ctx.GetAny(attemptKey).(int)
+	attempt++
+	r.Ctx.Put(attemptKey, attempt)
+	c.log.Warn("Request failed ☠️!", logger.URLKey, r.Request.URL.String(), logger.StatusKey, r.StatusCode,
+		logger.AttemptKey, attempt, logger.ErrorKey, err)
+
+	if ctxErr := c.ctx.Err(); ctxErr != nil {
+		r.Ctx.Put(failureKey, fmt.Errorf("request to %s was canceled: %w", r.Request.URL, ctxErr))
+		return
+	}
+	if !isRetryable(r.StatusCode) {
+		r.Ctx.Put(failureKey, requestError(r, err))
+		return
+	}
+	if attempt > c.config.retries {
+		r.Ctx.Put(failureKey, fmt.Errorf("reached max number of retries: %w", requestError(r, err)))
+		return
+	}
+
+	var headers http.Header
+	if r.Headers != nil {
+		headers = *r.Headers
+	}
+	wait, ok := c.config.retryWait(r.StatusCode, headers, attempt)
+	if !ok {
+		r.Ctx.Put(failureKey, fmt.Errorf("asked to retry after more than %s: %w", c.config.maxBackoff, requestError(r, err)))
+		return
+	}
+	c.log.Info("Retrying 🧌!", logger.URLKey, r.Request.URL.String(), logger.AttemptKey, attempt,
+		"retries", c.config.retries, "wait", wait.Round(time.Second))
+	if err := Sleep(c.ctx, wait); err != nil {
+		r.Ctx.Put(failureKey, fmt.Errorf("retrying %s was canceled: %w", r.Request.URL, err))
+		return
+	}
+	metrics.ObserveRetry(c.source)
+	r.Request.Retry()
+}
+
+// requestError returns the typed error of a failed request, a NetworkError if there is no response
+func requestError(r *colly.Response, err error) error {
+	url := r.Request.URL.String()
+	if r.StatusCode == 0 {
+		return &NetworkError{URL: url, Err: err}
+	}
+	return &StatusError{URL: url, StatusCode: r.StatusCode, Err: err}
+}
+
+// retryWait returns how long to wait before the given attempt, it honors the Retry-After header
+// and otherwise uses exponential backoff with jitter. It returns false when the Retry-After is longer
+// than the maximum backoff, the request is given up instead of holding the crawl for so long.
+func (config collectorConfig) retryWait(statusCode int, headers http.Header, attempt int) (time.Duration, bool) {
+	if statusCode == http.StatusTooManyRequests {
+		if wait, ok := parseRetryAfter(headers.Get("Retry-After"), time.Now()); ok {
+			return wait, wait <= config.maxBackoff
+		}
+	}
+
+	base := config.backoff
+	if statusCode == http.StatusTooManyRequests && config.tooManyWait > base {
+		base = config.tooManyWait
+	}
+
+	wait := base << (attempt - 1)
+	if wait <= 0 || wait > config.maxBackoff {
+		wait = config.maxBackoff
+	}
+	// Wait at least half of the backoff so a 429 is never retried right away
+	return wait/2 + time.Duration(rand.Float64()*float64(wait/2)), true
+}
+
+// Sleep waits for the duration, or until ctx is done in which case it returns the error of ctx
+func Sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// contextTransport cancels the requests in flight when ctx is done
+type contextTransport struct {
+	ctx  context.Context
+	base http.RoundTripper
+}
+
+func (t *contextTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return t.base.RoundTrip(req.WithContext(t.ctx))
+}
+
+// retryTransport makes the requests of HTTPClient with the timeout and retry policy of the collectors,
+// only the requests without a body are retried
+type retryTransport struct {
+	config collectorConfig
+	log    *slog.Logger
+	source string
+	base   http.RoundTripper
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	for attempt := 1; ; attempt++ {
+		resp, err := t.roundTrip(req)
+		statusCode := 0
+		if err == nil {
+			statusCode = resp.StatusCode
+		}
+		metrics.ObserveRequest(t.source, statusCode)
+		if !isRetryable(statusCode) || ctx.Err() != nil || (req.Body != nil && req.Body != http.NoBody) {
+			return resp, err
+		}
+
+		t.log.Warn("Request failed ☠️!", logger.URLKey, req.URL.String(), logger.StatusKey, statusCode,
+			logger.AttemptKey, attempt, logger.ErrorKey, err)
+		if attempt > t.config.retries {
+			return resp, err
+		}
+		var headers http.Header
+		if resp != nil {
+			headers = resp.Header
+		}
+		wait, ok := t.config.retryWait(statusCode, headers, attempt)
+		if !ok {
+			return resp, err
+		}
+		if resp != nil {
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+
+		t.log.Info("Retrying 🧌!", logger.URLKey, req.URL.String(), logger.AttemptKey, attempt,
+			"retries", t.config.retries, "wait", wait.Round(time.Second))
+		if err := Sleep(ctx, wait); err != nil {
+			return nil, err
+		}
+		metrics.ObserveRetry(t.source)
+	}
+}
+
+// roundTrip makes one attempt of the request with the timeout of the policy, which lasts until the body is closed
+func (t *retryTransport) roundTrip(req *http.Request) (*http.Response, error) {
+	ctx, cancel := context.WithTimeout(req.Context(), t.config.timeout)
+	resp, err := t.base.RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	resp.Body = &cancelBody{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+// cancelBody cancels the context of its request when it is closed
+type cancelBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
+
+// isRetryable reports whether a request that failed with the given status code is worth retrying,
+// status code 0 means the request failed before getting a response
+func isRetryable(statusCode int) bool {
+	switch {
+	case statusCode == 0:
+		return true
+	case statusCode == http.StatusTooManyRequests, statusCode == http.StatusRequestTimeout:
+		return true
+	case statusCode >= 500:
+		return true
+	default:
+		return false
+	}
+}
+
+// parseRetryAfter parses the value of a Retry-After header, which is either a number of seconds or an HTTP date
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		if wait := date.Sub(now); wait > 0 {
+			return wait, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
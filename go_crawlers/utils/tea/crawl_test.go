@@ -0,0 +1,389 @@
+package tea_test
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"fenjan.ai-hue.ir/tea"
+	"fenjan.ai-hue.ir/tea/fakeuni"
+	"github.com/gocolly/colly"
+	_ "modernc.org/sqlite"
+)
+
+// listSource crawls the list and detail pages of the fake website
+type listSource struct {
+	server  *fakeuni.Server
+	options []tea.CollectorOption
+	// untitled leaves the titles of the list page out of the listings
+	untitled bool
+}
+
+func (s listSource) Name() string      { return "Fake University" }
+func (s listSource) TableName() string { return "fake_edu" }
+
+// The fake website shows the application deadline of the positions
+func (s listSource) DateIsDeadline() bool { return true }
+
+func (s listSource) CollectorOptions() []tea.CollectorOption {
+	// No delay between the requests to keep the tests fast
+	return append([]tea.CollectorOption{tea.WithRateLimit(4, 0, 0)}, s.options...)
+}
+
+func (s listSource) List(ctx context.Context) (listings []tea.Listing, err error) {
+	c := tea.NewCollector(ctx)
+	c.OnHTML("li.job", func(e *colly.HTMLElement) {
+		listing := tea.Listing{URL: e.Request.AbsoluteURL(e.ChildAttr("a", "href"))}
+		if !s.untitled {
+			listing.Title = e.ChildText("a")
+		}
+		listings = append(listings, listing)
+	})
+	c.OnHTML("span.next a", func(e *colly.HTMLElement) {
+		c.Visit(e.Request.AbsoluteURL(e.Attr("href")))
+	})
+	err = c.Visit(s.server.ListURL())
+	return listings, err
+}
+
+func (s listSource) Detail(ctx context.Context, listing tea.Listing) (tea.Position, error) {
+	position := listing.Position()
+	c := tea.NewCollector(ctx)
+	c.OnHTML("h1.title", func(e *colly.HTMLElement) {
+		position.Title = strings.TrimSpace(e.Text)
+	})
+	c.OnHTML("div.description p", func(e *colly.HTMLElement) {
+		position.Description = strings.TrimSpace(e.Text)
+	})
+	c.OnHTML("span.deadline", func(e *colly.HTMLElement) {
+		position.Date = strings.TrimSpace(e.Text)
+	})
+	c.OnHTML("div.department", func(e *colly.HTMLElement) {
+		position.Department = strings.TrimSpace(e.Text)
+	})
+	err := c.Visit(position.URL)
+	return position, err
+}
+
+func fakePositions(n int) []fakeuni.Position {
+	var positions []fakeuni.Position
+	for i := 1; i <= n; i++ {
+		positions = append(positions, fakeuni.Position{
+			ID:          fmt.Sprint(i),
+			Title:       fmt.Sprintf("Doctoral student in topic %d", i),
+			Description: fmt.Sprintf("Position %d is funded for four years.", i),
+			Department:  "Department of Physics",
+			Deadline:    "2030-06-30",
+		})
+	}
+	return positions
+}
+
+func openSQLiteStore(t *testing.T) *tea.SQLStore {
+	db, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "fenjan.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	db.SetMaxOpenConns(1)
+	store := tea.NewSQLiteStore(db)
+	t.Cleanup(func() { store.Close() })
+	return store
+}
+
+func TestCrawlPaginationDedupAndClose(t *testing.T) {
+	positions := fakePositions(12)
+	positions[1].Title = "Doctoral student in Computer Vision"
+	server := fakeuni.New(positions...)
+	defer server.Close()
+	store := openSQLiteStore(t)
+	source := listSource{server: server}
+
+	result, err := tea.Crawl(context.Background(), store, source)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Listed != 12 || result.New != 12 || result.Failed != 0 {
+		t.Errorf("first crawl: %+v, want 12 listed and new positions", result)
+	}
+	if n := server.Requests("/jobs"); n != 3 {
+		t.Errorf("visited %d list pages, want 3", n)
+	}
+
+	// Nothing changed
+	result, err = tea.Crawl(context.Background(), store, source)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.New != 0 || result.Changed != 0 || result.Unchanged != 12 {
+		t.Errorf("second crawl: %+v, want 12 unchanged positions", result)
+	}
+
+	// One position is edited and one is removed
+	positions[0].Description = "The funding is extended to five years."
+	server.SetPositions(positions[:11]...)
+	result, err = tea.Crawl(context.Background(), store, source)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Changed != 1 || result.Unchanged != 10 || result.Closed != 1 {
+		t.Errorf("third crawl: %+v, want 1 changed, 10 unchanged and 1 closed position", result)
+	}
+
+	var description, deadline, category string
+	var confidence float64
+	err = store.DB().QueryRow("SELECT description, deadline, category, category_confidence FROM positions WHERE url = ?",
+		server.DetailURL("1")).Scan(&description, &deadline, &category, &confidence)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if description != positions[0].Description || !strings.HasPrefix(deadline, "2030-06-30") {
+		t.Errorf("saved description %q and deadline %q", description, deadline)
+	}
+	if category != "phd" || confidence <= 0.5 {
+		t.Errorf("saved category %q with confidence %.2f, want phd", category, confidence)
+	}
+	var fields []string
+	rows, err := store.DB().Query("SELECT field FROM position_fields WHERE url = ? ORDER BY field", server.DetailURL("2"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for rows.Next() {
+		var field string
+		rows.Scan(&field)
+		fields = append(fields, field)
+	}
+	rows.Close()
+	if strings.Join(fields, ",") != "computer-science,computer-vision" {
+		t.Errorf("saved fields %v, want computer-science and computer-vision", fields)
+	}
+	var closed int
+	if err := store.DB().QueryRow("SELECT COUNT(*) FROM positions WHERE closed_on IS NOT NULL").Scan(&closed); err != nil {
+		t.Fatal(err)
+	}
+	if closed != 1 {
+		t.Errorf("%d positions are closed, want 1", closed)
+	}
+}
+
+func TestCrawlRetriesTooManyRequestsAfterRetryAfter(t *testing.T) {
+	server := fakeuni.New(fakePositions(3)...)
+	defer server.Close()
+	server.TooManyRequests("/jobs/2", 2, "1")
+	store := tea.NewMemoryStore()
+
+	start := time.Now()
+	result, err := tea.Crawl(context.Background(), store, listSource{server: server})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.New != 3 || result.Failed != 0 {
+		t.Errorf("crawl: %+v, want 3 new positions", result)
+	}
+	if n := server.Requests("/jobs/2"); n != 3 {
+		t.Errorf("requested the throttled page %d times, want 3", n)
+	}
+	if elapsed := time.Since(start); elapsed < 2*time.Second {
+		t.Errorf("the crawl took %s, the Retry-After of 1 second wasn't waited twice", elapsed)
+	}
+}
+
+func TestCrawlGivesUpOnTooManyRequests(t *testing.T) {
+	server := fakeuni.New(fakePositions(3)...)
+	defer server.Close()
+	server.TooManyRequests("/jobs/3", 10, "0")
+	store := tea.NewMemoryStore()
+
+	source := listSource{server: server, options: []tea.CollectorOption{tea.WithRetries(2)}}
+	result, err := tea.Crawl(context.Background(), store, source)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.New != 2 || result.Failed != 1 {
+		t.Errorf("crawl: %+v, want 2 new and 1 failed position", result)
+	}
+	if n := server.Requests("/jobs/3"); n != 3 {
+		t.Errorf("requested the throttled page %d times, want 1 request and 2 retries", n)
+	}
+}
+
+func TestCrawlGivesUpOnLongRetryAfter(t *testing.T) {
+	server := fakeuni.New(fakePositions(3)...)
+	defer server.Close()
+	server.TooManyRequests("/jobs/3", 1, "3600")
+	store := tea.NewMemoryStore()
+
+	source := listSource{server: server, options: []tea.CollectorOption{tea.WithBackoff(time.Millisecond, time.Minute)}}
+	start := time.Now()
+	result, err := tea.Crawl(context.Background(), store, source)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.New != 2 || result.Failed != 1 {
+		t.Errorf("crawl: %+v, want 2 new and 1 failed position", result)
+	}
+	if n := server.Requests("/jobs/3"); n != 1 {
+		t.Errorf("requested the throttled page %d times, want no retry", n)
+	}
+	if elapsed := time.Since(start); elapsed > 30*time.Second {
+		t.Errorf("the crawl took %s, it waited for the Retry-After of an hour", elapsed)
+	}
+}
+
+func TestCrawlSlowDetailTimesOut(t *testing.T) {
+	server := fakeuni.New(fakePositions(4)...)
+	defer server.Close()
+	server.Slow("/jobs/4", 5*time.Second)
+	store := tea.NewMemoryStore()
+
+	source := listSource{server: server, options: []tea.CollectorOption{
+		tea.WithTimeout(200 * time.Millisecond), tea.WithRetries(1), tea.WithBackoff(10*time.Millisecond, 10*time.Millisecond),
+	}}
+	result, err := tea.Crawl(context.Background(), store, source)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.New != 3 || result.Failed != 1 {
+		t.Errorf("crawl: %+v, want 3 new and 1 failed position", result)
+	}
+	if n := server.Requests("/jobs/4"); n != 2 {
+		t.Errorf("requested the slow page %d times, want 2", n)
+	}
+	if saved := store.Positions(source.TableName()); len(saved) != 3 {
+		t.Errorf("saved %d positions, want 3", len(saved))
+	}
+}
+
+func TestCrawlStoppedSavesExtractedPositions(t *testing.T) {
+	server := fakeuni.New(fakePositions(4)...)
+	defer server.Close()
+	server.Slow("/jobs/4", 5*time.Second)
+	store := tea.NewMemoryStore()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	result, err := tea.Crawl(ctx, store, listSource{server: server})
+	if tea.ErrorKind(err) != "canceled" {
+		t.Fatalf("crawl returned %v, want the error of the context", err)
+	}
+	if result.New != 3 {
+		t.Errorf("crawl: %+v, want the 3 positions extracted before the timeout", result)
+	}
+}
+
+// stoppingSource stops the crawl when the details of the last position are extracted
+type stoppingSource struct {
+	listSource
+	stop context.CancelFunc
+	last string
+}
+
+func (s stoppingSource) Detail(ctx context.Context, listing tea.Listing) (tea.Position, error) {
+	position, err := s.listSource.Detail(ctx, listing)
+	if listing.URL == s.last {
+		s.stop()
+	}
+	return position, err
+}
+
+func TestCrawlStoppedKeepsPositionExtractedAtTheEnd(t *testing.T) {
+	server := fakeuni.New(fakePositions(1)...)
+	defer server.Close()
+	store := tea.NewMemoryStore()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	source := stoppingSource{listSource: listSource{server: server}, stop: cancel, last: server.DetailURL("1")}
+	result, err := tea.Crawl(ctx, store, source)
+	if tea.ErrorKind(err) != "canceled" {
+		t.Fatalf("crawl returned %v, want the error of the context", err)
+	}
+	if result.New != 1 || result.Failed != 0 {
+		t.Errorf("crawl: %+v, want the position extracted as the crawl was stopped", result)
+	}
+}
+
+// publishedSource reads the dates of the fake website as publication dates
+type publishedSource struct {
+	listSource
+}
+
+func (publishedSource) DateIsDeadline() bool { return false }
+
+func TestCrawlParsesDeadlineFromDate(t *testing.T) {
+	server := fakeuni.New(fakePositions(1)...)
+	defer server.Close()
+
+	for _, source := range []tea.Source{listSource{server: server}, publishedSource{listSource{server: server}}} {
+		store := tea.NewMemoryStore()
+		if _, err := tea.Crawl(context.Background(), store, source); err != nil {
+			t.Fatal(err)
+		}
+		_, datesAreDeadlines := source.(listSource)
+		position := store.Positions("fake_edu")[0]
+		if position.Date != "2030-06-30" || position.Deadline.IsZero() != !datesAreDeadlines {
+			t.Errorf("%T: position with date %q has deadline %s", source, position.Date, position.Deadline)
+		}
+	}
+}
+
+// doctoralSource only keeps the doctoral positions of the fake website
+type doctoralSource struct {
+	listSource
+}
+
+func (doctoralSource) TitleRules() tea.TitleRules {
+	return tea.TitleRules{Target: []string{"Doctoral"}, Forbidden: []string{"Postdoctoral"}}
+}
+
+func TestCrawlRejectsTitles(t *testing.T) {
+	server := fakeuni.New(
+		fakeuni.Position{ID: "1", Title: "Doctoral student in Physics", Description: "Four years."},
+		fakeuni.Position{ID: "2", Title: "Postdoctoral researcher in Physics", Description: "Two years."},
+		fakeuni.Position{ID: "3", Title: "Laboratory engineer", Description: "Permanent."},
+	)
+	defer server.Close()
+
+	for _, untitled := range []bool{false, true} {
+		store := openSQLiteStore(t)
+		source := doctoralSource{listSource{server: server, untitled: untitled}}
+		result, err := tea.Crawl(context.Background(), store, source)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result.Listed != 3 || result.New != 1 || result.Rejected != 2 {
+			t.Errorf("crawl with untitled listings %t: %+v, want 3 listed, 1 new and 2 rejected positions", untitled, result)
+		}
+
+		// The rejected positions are saved once with their reason
+		if _, err := tea.Crawl(context.Background(), store, source); err != nil {
+			t.Fatal(err)
+		}
+		rows, err := store.DB().Query("SELECT url, reason, keyword FROM rejected_positions ORDER BY url")
+		if err != nil {
+			t.Fatal(err)
+		}
+		var rejected []string
+		for rows.Next() {
+			var url, reason string
+			var keyword sql.NullString
+			if err := rows.Scan(&url, &reason, &keyword); err != nil {
+				t.Fatal(err)
+			}
+			rejected = append(rejected, strings.TrimPrefix(url, server.URL)+" "+reason+" "+keyword.String)
+		}
+		rows.Close()
+		want := []string{"/jobs/2 forbidden_keyword Postdoctoral", "/jobs/3 no_target_keyword "}
+		if strings.Join(rejected, "|") != strings.Join(want, "|") {
+			t.Errorf("rejected positions %q, want %q", rejected, want)
+		}
+	}
+
+	// The listings rejected by their title on the list page aren't visited, only the 2 crawls of the untitled ones visit them
+	if n := server.Requests("/jobs/2"); n != 2 {
+		t.Errorf("visited the rejected position %d times, want only by the 2 crawls of the untitled listings", n)
+	}
+}
@@ -0,0 +1,247 @@
+// Package deadline parses the application deadlines written on the university websites,
+// e.g. "31.01.2024", "Bewerbungsende: 15. März 2024", "15 maaliskuuta 2024" or "in 2 weeks"
+package deadline
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// months maps the month names and their abbreviations in English, German, Swedish, Finnish
+// and Dutch to the month, the Finnish names are stems because they are inflected, e.g. "maaliskuuta"
+var months = map[string]time.Month{
+	// English
+	"january": time.January, "february": time.February, "march": time.March, "april": time.April,
+	"may": time.May, "june": time.June, "july": time.July, "august": time.August,
+	"september": time.September, "october": time.October, "november": time.November, "december": time.December,
+	"jan": time.January, "feb": time.February, "mar": time.March, "apr": time.April,
+	"jun": time.June, "jul": time.July, "aug": time.August, "sep": time.September, "sept": time.September,
+	"oct": time.October, "nov": time.November, "dec": time.December,
+	// German
+	"januar": time.January, "jänner": time.January, "februar": time.February, "märz": time.March,
+	"maerz": time.March, "mai": time.May, "juni": time.June, "juli": time.July,
+	"oktober": time.October, "dezember": time.December, "mär": time.March, "okt": time.October, "dez": time.December,
+	// Swedish
+	"januari": time.January, "februari": time.February, "mars": time.March, "maj": time.May,
+	"augusti": time.August,
+	// Finnish
+	"tammikuu": time.January, "helmikuu": time.February, "maaliskuu": time.March, "huhtikuu": time.April,
+	"toukokuu": time.May, "kesäkuu": time.June, "heinäkuu": time.July, "elokuu": time.August,
+	"syyskuu": time.September, "lokakuu": time.October, "marraskuu": time.November, "joulukuu": time.December,
+	// Dutch
+	"maart": time.March, "mei": time.May, "augustus": time.August,
+}
+
+// units maps the words of the relative phrases to the number of days, months and years they add
+var units = map[string][3]int{
+	// English
+	"day": {1, 0, 0}, "days": {1, 0, 0}, "week": {7, 0, 0}, "weeks": {7, 0, 0},
+	"month": {0, 1, 0}, "months": {0, 1, 0}, "year": {0, 0, 1}, "years": {0, 0, 1},
+	// German
+	"tag": {1, 0, 0}, "tage": {1, 0, 0}, "tagen": {1, 0, 0}, "woche": {7, 0, 0}, "wochen": {7, 0, 0},
+	"monat": {0, 1, 0}, "monate": {0, 1, 0}, "monaten": {0, 1, 0},
+	// Swedish
+	"dag": {1, 0, 0}, "dagar": {1, 0, 0}, "vecka": {7, 0, 0}, "veckor": {7, 0, 0},
+	"månad": {0, 1, 0}, "månader": {0, 1, 0},
+	// Finnish
+	"päivä": {1, 0, 0}, "päivää": {1, 0, 0}, "päivän": {1, 0, 0}, "viikko": {7, 0, 0}, "viikkoa": {7, 0, 0},
+	"kuukausi": {0, 1, 0}, "kuukautta": {0, 1, 0},
+	// Dutch
+	"dagen": {1, 0, 0}, "weken": {7, 0, 0}, "maand": {0, 1, 0}, "maanden": {0, 1, 0},
+}
+
+// ambiguousMonths are the month names that are common words too, e.g. "you may apply", they are only
+// months in a date with a year like "15 May 2025" or "May 15, 2025"
+var ambiguousMonths = map[string]bool{"may": true, "mar": true}
+
+// days maps the words that name a day relative to today to the number of days they add,
+// "morgen" is also "morning" in German so it is only tomorrow on its own or in "bis morgen"
+var days = map[string]int{
+	"today": 0, "heute": 0, "idag": 0, "tänään": 0, "vandaag": 0,
+	"tomorrow": 1, "imorgon": 1, "huomenna": 1,
+}
+
+var ordinalSuffixes = map[string]bool{"st": true, "nd": true, "rd": true, "th": true}
+
+var (
+	isoDate     = regexp.MustCompile(`\b(\d{4})-(\d{1,2})-(\d{1,2})\b`)
+	numericDate = regexp.MustCompile(`\b(\d{1,2})\s*[./]\s*(\d{1,2})\s*[./]\s*(\d{4}|\d{2})\b`)
+	words       = regexp.MustCompile(`[\p{L}]+|\d+`)
+)
+
+// Parse finds the deadline in the text, now is used for the year when the text doesn't have it
+// and for the relative phrases. It returns false if the text has no date.
+func Parse(text string, now time.Time) (time.Time, bool) {
+	text = strings.ToLower(text)
+
+	if match := isoDate.FindStringSubmatch(text); match != nil {
+		if date, ok := newDate(atoi(match[1]), atoi(match[2]), atoi(match[3]), now); ok {
+			return date, true
+		}
+	}
+
+	if match := numericDate.FindStringSubmatch(text); match != nil {
+		day, month, year := atoi(match[1]), atoi(match[2]), atoi(match[3])
+		if year < 100 {
+			year += 2000
+		}
+		// The day comes first in Europe, swap them only if the month can't be a month
+		if month > 12 && day <= 12 {
+			day, month = month, day
+		}
+		if date, ok := newDate(year, month, day, now); ok {
+			return date, true
+		}
+	}
+
+	tokens := tokenize(text)
+	if date, ok := parseMonthName(tokens, now); ok {
+		return date, true
+	}
+	return parseRelative(text, tokens, now)
+}
+
+// tokenize splits the text into words and numbers, leaving out the ordinal suffixes and "of",
+// so "15th of March" becomes "15 march"
+func tokenize(text string) []string {
+	var tokens []string
+	for _, token := range words.FindAllString(text, -1) {
+		previousIsNumber := len(tokens) > 0 && isNumber(tokens[len(tokens)-1])
+		if token == "of" || (previousIsNumber && ordinalSuffixes[token]) {
+			continue
+		}
+		tokens = append(tokens, token)
+	}
+	return tokens
+}
+
+// parseMonthName finds a date written with a month name, e.g. "15 March 2024", "March 15th, 2024"
+// or "15. maaliskuuta 2024", the year is optional
+func parseMonthName(tokens []string, now time.Time) (time.Time, bool) {
+	for i, token := range tokens {
+		month, ok := monthOf(token)
+		if !ok {
+			continue
+		}
+
+		// The day is the number before the month, or after it
+		day, end := 0, i
+		if i > 0 && isDay(tokens[i-1]) {
+			day = atoi(tokens[i-1])
+		} else if i+1 < len(tokens) && isDay(tokens[i+1]) {
+			day, end = atoi(tokens[i+1]), i+1
+		}
+		if day == 0 {
+			continue
+		}
+
+		// The year is the first four digit number after the month
+		year, yearIndex := 0, -1
+		for j := i + 1; j < len(tokens); j++ {
+			if len(tokens[j]) == 4 && isNumber(tokens[j]) {
+				year, yearIndex = atoi(tokens[j]), j
+				break
+			}
+		}
+		if ambiguousMonths[token] && yearIndex != end+1 {
+			continue
+		}
+		if date, ok := newDate(year, int(month), day, now); ok {
+			return date, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// parseRelative finds a relative phrase, e.g. "today", "tomorrow", "in 3 days" or "om 2 veckor"
+func parseRelative(text string, tokens []string, now time.Time) (time.Time, bool) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	// "i morgon" is written in two words in Swedish
+	if strings.Contains(text, "i morgon") {
+		return today.AddDate(0, 0, 1), true
+	}
+
+	for i, token := range tokens {
+		if n, ok := days[token]; ok {
+			return today.AddDate(0, 0, n), true
+		}
+		if token == "morgen" && (len(tokens) == 1 || (i > 0 && tokens[i-1] == "bis")) {
+			return today.AddDate(0, 0, 1), true
+		}
+		if i+1 < len(tokens) && isNumber(token) {
+			if unit, ok := units[tokens[i+1]]; ok {
+				n := atoi(token)
+				return today.AddDate(n*unit[2], n*unit[1], n*unit[0]), true
+			}
+		}
+	}
+	return time.Time{}, false
+}
+
+// monthOf returns the month named by the word, the Finnish month names are matched by their stem
+func monthOf(word string) (time.Month, bool) {
+	if month, ok := months[word]; ok {
+		return month, true
+	}
+	if strings.Contains(word, "kuu") {
+		for name, month := range months {
+			if strings.HasSuffix(name, "kuu") && strings.HasPrefix(word, name) {
+				return month, true
+			}
+		}
+	}
+	return 0, false
+}
+
+// newDate returns the date if it is valid, a zero year is the year that makes the date closest to now
+func newDate(year, month, day int, now time.Time) (time.Time, bool) {
+	if month < 1 || month > 12 || day < 1 || day > 31 {
+		return time.Time{}, false
+	}
+
+	if year == 0 {
+		best := time.Time{}
+		for _, candidate := range []int{now.Year() - 1, now.Year(), now.Year() + 1} {
+			date, ok := newDate(candidate, month, day, now)
+			if ok && (best.IsZero() || absDuration(date.Sub(now)) < absDuration(best.Sub(now))) {
+				best = date
+			}
+		}
+		return best, !best.IsZero()
+	}
+
+	date := time.Date(year, time.Month(month), day, 0, 0, 0, 0, now.Location())
+	// time.Date normalizes invalid dates like 31 February
+	if date.Day() != day {
+		return time.Time{}, false
+	}
+	return date, true
+}
+
+func isDay(token string) bool {
+	return isNumber(token) && len(token) <= 2 && atoi(token) >= 1 && atoi(token) <= 31
+}
+
+func isNumber(token string) bool {
+	for _, r := range token {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return token != ""
+}
+
+func atoi(s string) int {
+	n, _ := strconv.Atoi(s)
+	return n
+}
+
+func absDuration(d time.Duration) time.Duration {
+	if d < 0 {
+		return -d
+	}
+	return d
+}
@@ -0,0 +1,85 @@
+package deadline
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	now := time.Date(2024, time.November, 20, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		text string
+		want string // "" when the text has no date
+	}{
+		// ISO dates
+		{"2024-01-31", "2024-01-31"},
+		{"Deadline: 2025-1-5 23:59", "2025-01-05"},
+		// Numeric dates, the day comes first unless the month can't be a month
+		{"31.01.2024", "2024-01-31"},
+		{"31.1.24", "2024-01-31"},
+		{"05/03/2025", "2025-03-05"},
+		{"03/25/2025", "2025-03-25"},
+		{"15 . 03 . 2025", "2025-03-15"},
+		// English
+		{"15 March 2025", "2025-03-15"},
+		{"March 15th, 2025", "2025-03-15"},
+		{"Apply by: 15th of March 2025", "2025-03-15"},
+		{"Last application date: 1 Dec 2024", "2024-12-01"},
+		// "may" and "mar" are only months with a year right after the date
+		{"15 May 2025", "2025-05-15"},
+		{"May 15th, 2025", "2025-05-15"},
+		{"1 Mar 2025", "2025-03-01"},
+		{"The position may start 1 January 2025", "2025-01-01"},
+		// German
+		{"Bewerbungsende: 15. März 2025", "2025-03-15"},
+		{"bis zum 1. Dezember 2024", "2024-12-01"},
+		// Swedish
+		{"Sista ansökningsdag: 3 februari 2025", "2025-02-03"},
+		{"31 augusti 2025", "2025-08-31"},
+		// Finnish, the inflected month names are matched by their "kuu" stem
+		{"15. maaliskuuta 2025", "2025-03-15"},
+		{"Haku päättyy 1. joulukuuta 2024", "2024-12-01"},
+		{"kesäkuun 30. 2025", "2025-06-30"},
+		// Dutch
+		{"Sluitingsdatum: 31 mei 2025", "2025-05-31"},
+		{"1 maart 2025", "2025-03-01"},
+		// Without a year the date closest to now is taken, so it rolls over to the next year
+		{"15 January", "2025-01-15"},
+		{"20 December", "2024-12-20"},
+		{"1 November", "2024-11-01"},
+		// Relative phrases
+		{"in 2 weeks", "2024-12-04"},
+		{"in 3 days", "2024-11-23"},
+		{"in 1 month", "2024-12-20"},
+		{"in 3 Tagen", "2024-11-23"},
+		{"om 2 veckor", "2024-12-04"},
+		{"2 kuukautta", "2025-01-20"},
+		{"over 2 weken", "2024-12-04"},
+		{"today", "2024-11-20"},
+		{"i morgon", "2024-11-21"},
+		{"Morgen", "2024-11-21"},
+		{"Bewerbung bis morgen", "2024-11-21"},
+		{"huomenna", "2024-11-21"},
+		// Texts without a date
+		{"", ""},
+		{"Open until filled", ""},
+		{"You may apply at any time", ""},
+		{"Up to 3 may be hired by 2025", ""},
+		{"15 May", ""},
+		{"Reference MAR 12", ""},
+		{"Montag Morgen um 9 Uhr", ""},
+		{"Reference number 2024/123", ""},
+		{"31.02.2025", ""},
+		{"13.13.2025", ""},
+	}
+	for _, test := range tests {
+		date, ok := Parse(test.text, now)
+		got := ""
+		if ok {
+			got = date.Format("2006-01-02")
+		}
+		if got != test.want {
+			t.Errorf("Parse(%q) = %q, want %q", test.text, got, test.want)
+		}
+	}
+}
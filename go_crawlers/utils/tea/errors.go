@@ -0,0 +1,96 @@
+package tea
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// NetworkError is a request that failed without a response, e.g. a timeout or a refused connection
+type NetworkError struct {
+	URL string
+	Err error
+}
+
+func (e *NetworkError) Error() string {
+	return fmt.Sprintf("request to %s failed: %v", e.URL, e.Err)
+}
+
+func (e *NetworkError) Unwrap() error { return e.Err }
+
+// StatusError is a request whose response has an error status code
+type StatusError struct {
+	URL        string
+	StatusCode int
+	Err        error
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("request to %s failed with status %d: %v", e.URL, e.StatusCode, e.Err)
+}
+
+func (e *StatusError) Unwrap() error { return e.Err }
+
+// ParseError is a response that doesn't have the expected format, e.g. invalid JSON,
+// or a page whose structure has changed
+type ParseError struct {
+	URL string
+	Err error
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("parsing %s failed: %v", e.URL, e.Err)
+}
+
+func (e *ParseError) Unwrap() error { return e.Err }
+
+// ZeroResultsError is a source whose list page has no positions, which usually means the page is broken
+type ZeroResultsError struct {
+	Source string
+}
+
+func (e *ZeroResultsError) Error() string {
+	return fmt.Sprintf("no positions are listed at the %s", e.Source)
+}
+
+// ErrorKind returns the kind of a crawl error as it is logged:
+// "network", "http_status", "parse", "zero_results", "canceled" or "other"
+func ErrorKind(err error) string {
+	var networkErr *NetworkError
+	var statusErr *StatusError
+	var parseErr *ParseError
+	var zeroErr *ZeroResultsError
+	switch {
+	case err == nil:
+		return ""
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return "canceled"
+	case errors.As(err, &zeroErr):
+		return "zero_results"
+	case errors.As(err, &parseErr):
+		return "parse"
+	case errors.As(err, &statusErr):
+		return "http_status"
+	case errors.As(err, &networkErr):
+		return "network"
+	default:
+		return "other"
+	}
+}
+
+// ErrorURL returns the URL of the request that caused a crawl error, empty if the error has none
+func ErrorURL(err error) string {
+	var networkErr *NetworkError
+	var statusErr *StatusError
+	var parseErr *ParseError
+	switch {
+	case errors.As(err, &parseErr):
+		return parseErr.URL
+	case errors.As(err, &statusErr):
+		return statusErr.URL
+	case errors.As(err, &networkErr):
+		return networkErr.URL
+	default:
+		return ""
+	}
+}
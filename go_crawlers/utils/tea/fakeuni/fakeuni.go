@@ -0,0 +1,292 @@
+// Package fakeuni is a fake university website served by httptest, for the end-to-end tests of the crawlers.
+//
+// It serves the same positions in the ways the real websites do: list pages with pagination,
+// detail pages, an RSS feed and a JSON AJAX endpoint like the one of the University of Helsinki.
+// Any path can be made to answer 429 Too Many Requests or to respond slowly.
+//
+//	/jobs?page=N     the list page N, starting at 1, with a "span.next a" and an "a.pager__link--next" link
+//	/jobs/<id>       the detail page of a position, rendered with DetailTemplate
+//	/jobs.rss        the RSS feed of all positions
+//	/ajax_get_jobs   the AJAX commands with the JSON of all positions in their data
+package fakeuni
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"html/template"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Position is a position advertised on the fake website
+type Position struct {
+	ID          string
+	Title       string
+	Description string
+	Department  string
+	// Deadline is the application deadline as "2006-01-02"
+	Deadline string
+}
+
+// DefaultDetailTemplate renders the detail page of a position, its fields are in
+// "h1.title", "div.department", "div.description p" and "span.deadline"
+var DefaultDetailTemplate = template.Must(template.New("detail").Parse(`<!DOCTYPE html>
+<html><head><title>{{.Title}}</title></head>
+<body>
+<h1 class="title">{{.Title}}</h1>
+<div class="department">{{.Department}}</div>
+<div class="description"><p>{{.Description}}</p></div>
+<p>Apply by <span class="deadline">{{.Deadline}}</span></p>
+</body></html>
+`))
+
+var listTemplate = template.Must(template.New("list").Parse(`<!DOCTYPE html>
+<html><head><title>Vacancies</title></head>
+<body>
+<ul class="jobs">
+{{range .Positions}}<li class="job"><a href="/jobs/{{.ID}}">{{.Title}}</a> <span class="department">{{.Department}}</span> <span class="deadline">{{.Deadline}}</span></li>
+{{end}}</ul>
+{{if .Next}}<nav class="pager"><span class="next"><a href="/jobs?page={{.Next}}">Next</a></span> <a class="pager__link pager__link--next" href="/jobs?page={{.Next}}">›</a></nav>{{end}}
+</body></html>
+`))
+
+// fault makes the responses of a path fail
+type fault struct {
+	// tooManyRequests is the number of 429 responses left
+	tooManyRequests int
+	retryAfter      string
+	delay           time.Duration
+}
+
+// Server is the fake website, the fields must be set before it gets requests
+type Server struct {
+	*httptest.Server
+	// PageSize is the number of positions on a list page, 5 by default
+	PageSize int
+	// DetailTemplate renders the detail pages, DefaultDetailTemplate by default
+	DetailTemplate *template.Template
+	// LoopLastPage makes the next link of the last list page point to the page itself, like the broken
+	// pagers of some websites
+	LoopLastPage bool
+
+	mu        sync.Mutex
+	positions []Position
+	faults    map[string]*fault
+	requests  map[string]int
+}
+
+// New starts a fake website with the positions, it is closed by Close
+func New(positions ...Position) *Server {
+	s := &Server{
+		PageSize:       5,
+		DetailTemplate: DefaultDetailTemplate,
+		positions:      positions,
+		faults:         map[string]*fault{},
+		requests:       map[string]int{},
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// SetPositions replaces the advertised positions, e.g. to test a position that changed or is removed
+func (s *Server) SetPositions(positions ...Position) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.positions = positions
+}
+
+// TooManyRequests answers the next times requests of the path with 429 Too Many Requests,
+// with the Retry-After header unless retryAfter is empty
+func (s *Server) TooManyRequests(path string, times int, retryAfter string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.fault(path).tooManyRequests = times
+	s.fault(path).retryAfter = retryAfter
+}
+
+// Slow delays the responses of the path, the response is dropped if the client gives up before
+func (s *Server) Slow(path string, delay time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.fault(path).delay = delay
+}
+
+// Requests returns the number of requests made to the path
+func (s *Server) Requests(path string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requests[path]
+}
+
+// ListURL returns the URL of the first list page
+func (s *Server) ListURL() string { return s.URL + "/jobs" }
+
+// DetailURL returns the URL of the detail page of a position
+func (s *Server) DetailURL(id string) string { return s.URL + "/jobs/" + id }
+
+// FeedURL returns the URL of the RSS feed
+func (s *Server) FeedURL() string { return s.URL + "/jobs.rss" }
+
+// AjaxURL returns the URL of the AJAX endpoint
+func (s *Server) AjaxURL() string { return s.URL + "/ajax_get_jobs" }
+
+// fault returns the fault of the path, creating it if there is none, s.mu must be held
+func (s *Server) fault(path string) *fault {
+	if s.faults[path] == nil {
+		s.faults[path] = &fault{}
+	}
+	return s.faults[path]
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	s.requests[r.URL.Path]++
+	var delay time.Duration
+	tooManyRequests, retryAfter := false, ""
+	if f := s.faults[r.URL.Path]; f != nil {
+		delay = f.delay
+		if f.tooManyRequests > 0 {
+			f.tooManyRequests--
+			tooManyRequests, retryAfter = true, f.retryAfter
+		}
+	}
+	positions := append([]Position(nil), s.positions...)
+	s.mu.Unlock()
+
+	if delay > 0 {
+		if sleep(r.Context(), delay) != nil {
+			return
+		}
+	}
+	if tooManyRequests {
+		if retryAfter != "" {
+			w.Header().Set("Retry-After", retryAfter)
+		}
+		http.Error(w, "Too Many Requests", http.StatusTooManyRequests)
+		return
+	}
+
+	switch {
+	case r.URL.Path == "/jobs":
+		s.serveList(w, r, positions)
+	case strings.HasPrefix(r.URL.Path, "/jobs/"):
+		s.serveDetail(w, strings.TrimPrefix(r.URL.Path, "/jobs/"), positions)
+	case r.URL.Path == "/jobs.rss":
+		s.serveFeed(w, positions)
+	case r.URL.Path == "/ajax_get_jobs":
+		s.serveAjax(w, positions)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (s *Server) serveList(w http.ResponseWriter, r *http.Request, positions []Position) {
+	page, err := strconv.Atoi(r.URL.Query().Get("page"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+	start, end := (page-1)*s.PageSize, page*s.PageSize
+	if start > len(positions) {
+		start = len(positions)
+	}
+	next := 0
+	if end < len(positions) {
+		next = page + 1
+	} else {
+		end = len(positions)
+		if s.LoopLastPage {
+			next = page
+		}
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	listTemplate.Execute(w, struct {
+		Positions []Position
+		Next      int
+	}{positions[start:end], next})
+}
+
+func (s *Server) serveDetail(w http.ResponseWriter, id string, positions []Position) {
+	for _, position := range positions {
+		if position.ID == id {
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			s.DetailTemplate.Execute(w, position)
+			return
+		}
+	}
+	http.Error(w, "Not Found", http.StatusNotFound)
+}
+
+func (s *Server) serveFeed(w http.ResponseWriter, positions []Position) {
+	type item struct {
+		Title       string `xml:"title"`
+		Link        string `xml:"link"`
+		Description string `xml:"description"`
+		GUID        string `xml:"guid"`
+	}
+	type rss struct {
+		XMLName xml.Name `xml:"rss"`
+		Version string   `xml:"version,attr"`
+		Title   string   `xml:"channel>title"`
+		Link    string   `xml:"channel>link"`
+		Items   []item   `xml:"channel>item"`
+	}
+
+	feed := rss{Version: "2.0", Title: "Vacancies", Link: s.ListURL()}
+	for _, position := range positions {
+		url := s.DetailURL(position.ID)
+		feed.Items = append(feed.Items, item{Title: position.Title, Link: url, Description: position.Description, GUID: url})
+	}
+	w.Header().Set("Content-Type", "application/rss+xml; charset=utf-8")
+	fmt.Fprint(w, xml.Header)
+	xml.NewEncoder(w).Encode(feed)
+}
+
+func (s *Server) serveAjax(w http.ResponseWriter, positions []Position) {
+	type job struct {
+		Title         string `json:"title"`
+		URL           string `json:"url"`
+		Department    string `json:"department"`
+		Date          string `json:"date"`
+		DateTimestamp int64  `json:"date_timestamp"`
+		Description   string `json:"description"`
+		Key           int    `json:"key"`
+	}
+
+	var jobs []job
+	for i, position := range positions {
+		j := job{Title: position.Title, URL: s.DetailURL(position.ID), Department: position.Department,
+			Date: position.Deadline, Description: position.Description, Key: i + 1}
+		if deadline, err := time.Parse("2006-01-02", position.Deadline); err == nil {
+			j.DateTimestamp = deadline.Unix()
+		}
+		jobs = append(jobs, j)
+	}
+	data, err := json.Marshal(jobs)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode([]map[string]interface{}{
+		{"command": "insert", "method": "html", "selector": ".jobs", "data": string(data), "settings": nil},
+	})
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
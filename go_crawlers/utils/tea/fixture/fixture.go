@@ -0,0 +1,135 @@
+// Package fixture records the responses of the university websites into files and replays them,
+// so the crawlers can be tested without network access.
+//
+// Each response is a JSON file in the fixture directory, named after its URL. Test crawls a source
+// with the responses of a directory and compares the positions with the golden.json file next to them.
+// Setting FENJAN_RECORD=1 records the responses of the live website and rewrites the golden file,
+// and FENJAN_UPDATE_GOLDEN=1 only rewrites the golden file from the recorded responses:
+//
+//	FENJAN_RECORD=1 go test ./kth_royal_institute_of_technology
+package fixture
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Response is a recorded response
+type Response struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Status int         `json:"status"`
+	Header http.Header `json:"header,omitempty"`
+	Body   string      `json:"body"`
+}
+
+var unsafeCharacters = regexp.MustCompile(`[^A-Za-z0-9]+`)
+
+// FileName returns the name of the fixture file of a request, e.g. "www_kth_se_en_om-3f2a9c1b.json",
+// the hash keeps the names of the URLs that only differ by their query apart
+func FileName(method, url string) string {
+	readable := url
+	if i := strings.Index(readable, "://"); i >= 0 {
+		readable = readable[i+3:]
+	}
+	readable = strings.Trim(unsafeCharacters.ReplaceAllString(readable, "_"), "_")
+	if len(readable) > 80 {
+		readable = readable[:80]
+	}
+	hash := sha256.Sum256([]byte(method + " " + url))
+	return readable + "-" + hex.EncodeToString(hash[:4]) + ".json"
+}
+
+// Recorder is a transport that makes the requests with Base and saves their responses into Dir
+type Recorder struct {
+	Dir string
+	// Base makes the requests, http.DefaultTransport if it is nil
+	Base http.RoundTripper
+}
+
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := r.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	resp, err := base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	recorded := Response{Method: req.Method, URL: req.URL.String(), Status: resp.StatusCode, Header: resp.Header, Body: string(body)}
+	data, err := json.MarshalIndent(recorded, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(r.Dir, os.ModePerm); err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(filepath.Join(r.Dir, FileName(req.Method, recorded.URL)), data, 0644); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// Replayer is a transport that answers the requests with the responses recorded in Dir,
+// a request without a recorded response gets a 404 Not Found, which isn't retried
+type Replayer struct {
+	Dir string
+}
+
+func (r *Replayer) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		req.Body.Close()
+	}
+
+	data, err := os.ReadFile(filepath.Join(r.Dir, FileName(req.Method, req.URL.String())))
+	if os.IsNotExist(err) {
+		return newResponse(req, Response{Status: http.StatusNotFound, Body: fmt.Sprintf("no fixture for %s %s", req.Method, req.URL)}), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var recorded Response
+	if err := json.Unmarshal(data, &recorded); err != nil {
+		return nil, fmt.Errorf("invalid fixture for %s: %w", req.URL, err)
+	}
+	return newResponse(req, recorded), nil
+}
+
+func newResponse(req *http.Request, recorded Response) *http.Response {
+	header := recorded.Header.Clone()
+	if header == nil {
+		header = http.Header{}
+	}
+	// The body is stored decoded
+	header.Del("Content-Encoding")
+	header.Del("Content-Length")
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", recorded.Status, http.StatusText(recorded.Status)),
+		StatusCode:    recorded.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(strings.NewReader(recorded.Body)),
+		ContentLength: int64(len(recorded.Body)),
+		Request:       req,
+	}
+}
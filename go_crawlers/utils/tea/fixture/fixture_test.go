@@ -0,0 +1,64 @@
+package fixture
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRecordAndReplay(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte("<h1>" + r.URL.Query().Get("page") + "</h1>"))
+	}))
+	defer server.Close()
+	dir := t.TempDir()
+
+	recorder := &http.Client{Transport: &Recorder{Dir: dir}}
+	for _, page := range []string{"1", "2"} {
+		resp, err := recorder.Get(server.URL + "/positions?page=" + page)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+	}
+	server.Close()
+
+	replayer := &http.Client{Transport: &Replayer{Dir: dir}}
+	for _, page := range []string{"1", "2"} {
+		resp, err := replayer.Get(server.URL + "/positions?page=" + page)
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK || string(body) != "<h1>"+page+"</h1>" {
+			t.Errorf("page %s replayed as %d %q", page, resp.StatusCode, body)
+		}
+		if resp.Header.Get("Content-Type") != "text/html" {
+			t.Errorf("page %s replayed with Content-Type %q", page, resp.Header.Get("Content-Type"))
+		}
+	}
+
+	resp, err := replayer.Get(server.URL + "/positions?page=3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("a request without fixture got %d, want 404", resp.StatusCode)
+	}
+}
+
+func TestFileName(t *testing.T) {
+	first := FileName(http.MethodGet, "https://www.kth.se/en/om/work-at-kth/doktorander-1.572201?page=1")
+	second := FileName(http.MethodGet, "https://www.kth.se/en/om/work-at-kth/doktorander-1.572201?page=2")
+	if first == second {
+		t.Errorf("the URLs that differ by their query have the same file name %q", first)
+	}
+	if !strings.HasPrefix(first, "www_kth_se_en_om_work_at_kth_doktorander_1_572201_page_1-") || !strings.HasSuffix(first, ".json") {
+		t.Errorf("unexpected file name %q", first)
+	}
+}
@@ -0,0 +1,152 @@
+package fixture
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"fenjan.ai-hue.ir/tea"
+)
+
+// metaFile keeps the time the responses were recorded, the deadlines are parsed at that time
+const metaFile = "meta.json"
+
+// goldenFile keeps the positions extracted from the responses
+const goldenFile = "golden.json"
+
+type meta struct {
+	RecordedOn time.Time `json:"recorded_on"`
+}
+
+// Recording reports whether the tests record the live websites, set by FENJAN_RECORD=1
+func Recording() bool {
+	return os.Getenv("FENJAN_RECORD") == "1"
+}
+
+// updatingGolden reports whether the golden files are rewritten from the recorded responses,
+// set by FENJAN_UPDATE_GOLDEN=1 after a change of a crawler that changes its positions on purpose
+func updatingGolden() bool {
+	return Recording() || os.Getenv("FENJAN_UPDATE_GOLDEN") == "1"
+}
+
+// Test crawls the source with the responses in dir, and compares the positions it extracts with
+// the ones in dir/golden.json. When Recording, it crawls the live website instead, saving its
+// responses and positions into dir.
+// It replaces tea.HTTPTransport and tea.Now during the crawl, so the tests using it can't run in parallel.
+func Test(t *testing.T, source tea.Source, dir string) {
+	t.Helper()
+
+	var m meta
+	if Recording() {
+		m.RecordedOn = time.Now().UTC().Truncate(time.Second)
+		// The responses of the previous recording may not be requested anymore
+		if err := os.RemoveAll(dir); err != nil {
+			t.Fatal(err)
+		}
+		writeJSON(t, filepath.Join(dir, metaFile), m)
+		setTransport(t, &Recorder{Dir: dir})
+	} else {
+		readJSON(t, filepath.Join(dir, metaFile), &m)
+		setTransport(t, &Replayer{Dir: dir})
+	}
+	now := tea.Now
+	tea.Now = func() time.Time { return m.RecordedOn }
+	t.Cleanup(func() { tea.Now = now })
+
+	store := tea.NewMemoryStore()
+	if _, err := tea.Crawl(context.Background(), store, source); err != nil {
+		t.Fatalf("crawling %s failed: %v", source.TableName(), err)
+	}
+	positions := store.Positions(source.TableName())
+	sort.Slice(positions, func(i, j int) bool { return positions[i].URL < positions[j].URL })
+
+	golden := filepath.Join(dir, goldenFile)
+	if updatingGolden() {
+		writeJSON(t, golden, positions)
+		return
+	}
+	var want []tea.Position
+	readJSON(t, golden, &want)
+	comparePositions(t, positions, want)
+}
+
+// comparePositions reports the positions that are missing, unexpected or different from the golden ones
+func comparePositions(t *testing.T, got, want []tea.Position) {
+	t.Helper()
+
+	wantByURL := map[string]tea.Position{}
+	for _, position := range want {
+		wantByURL[position.URL] = position
+	}
+	for _, position := range got {
+		expected, ok := wantByURL[position.URL]
+		if !ok {
+			t.Errorf("unexpected position %s", position.URL)
+			continue
+		}
+		delete(wantByURL, position.URL)
+		if !reflect.DeepEqual(normalize(t, position), normalize(t, expected)) {
+			gotJSON, _ := json.MarshalIndent(position, "", "  ")
+			wantJSON, _ := json.MarshalIndent(expected, "", "  ")
+			t.Errorf("position %s changed:\ngot  %s\nwant %s", position.URL, gotJSON, wantJSON)
+		}
+	}
+	for url := range wantByURL {
+		t.Errorf("missing position %s", url)
+	}
+}
+
+// normalize returns the position as it is written to the golden file, so times in different zones compare equal
+func normalize(t *testing.T, position tea.Position) map[string]interface{} {
+	data, err := json.Marshal(position)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		t.Fatal(err)
+	}
+	return fields
+}
+
+// setTransport makes the requests of the crawlers with the transport until the end of the test
+func setTransport(t *testing.T, transport http.RoundTripper) {
+	previous := tea.HTTPTransport
+	tea.HTTPTransport = transport
+	t.Cleanup(func() { tea.HTTPTransport = previous })
+}
+
+func writeJSON(t *testing.T, path string, value interface{}) {
+	t.Helper()
+	data, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func readJSON(t *testing.T, path string, value interface{}) {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("%s is missing, record the fixtures with FENJAN_RECORD=1", path)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(data, value); err != nil {
+		t.Fatalf("invalid %s: %v", path, err)
+	}
+}
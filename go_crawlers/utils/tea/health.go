@@ -0,0 +1,221 @@
+package tea
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"fenjan.ai-hue.ir/logger"
+)
+
+// HealthHistory is the number of recent crawls of a source that a crawl is compared with
+const HealthHistory = 10
+
+// MinHealthHistory is the number of successful recent crawls needed to compare the counts of a crawl,
+// before that only a crawl that lists nothing or extracts no title is reported
+const MinHealthHistory = 3
+
+// HealthListedFraction reports a crawl that lists less than this fraction of the median of the recent crawls
+const HealthListedFraction = 0.5
+
+// MaxEmptyRateIncrease reports a crawl whose fraction of positions without a title or a description
+// is higher than the median fraction of the recent crawls by more than this
+const MaxEmptyRateIncrease = 0.3
+
+// Alert reports a source whose website has probably changed, so its crawler needs to be fixed
+type Alert struct {
+	// Source is the table name of the source
+	Source string
+	// Name is the name of the university
+	Name     string
+	Problems []string
+	Record   CrawlRecord
+}
+
+// Notifier sends the alerts of the health check, e.g. to a chat or an e-mail
+type Notifier interface {
+	Notify(ctx context.Context, alert Alert) error
+}
+
+// HealthNotifier sends the alerts of the health check of Crawl, it logs them by default
+var HealthNotifier Notifier = LogNotifier{}
+
+// checkHealth compares a finished crawl with the recent crawls of the source, saves its record,
+// and sends an alert through HealthNotifier when the source looks broken.
+// Crawls that were stopped are not checked, they didn't get to see the whole website.
+func checkHealth(ctx context.Context, store Store, source Source, result CrawlResult, err error) {
+	if ctx.Err() != nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return
+	}
+	log := logger.ForSource(source.TableName())
+
+	record := CrawlRecord{
+		Listed:            result.Listed,
+		Extracted:         result.New + result.Changed + result.Unchanged,
+		Failed:            result.Failed,
+		EmptyTitles:       result.EmptyTitles,
+		EmptyDescriptions: result.EmptyDescriptions,
+		ErrorKind:         ErrorKind(err),
+		FinishedOn:        time.Now().UTC(),
+	}
+	history, historyErr := store.RecentCrawlRecords(source.TableName(), HealthHistory)
+	if historyErr != nil {
+		log.Error("Getting the recent crawls failed ☠️!", logger.ErrorKey, historyErr)
+		return
+	}
+	if err := store.SaveCrawlRecord(source.TableName(), record); err != nil {
+		log.Error("Saving the crawl record failed ☠️!", logger.ErrorKey, err)
+	}
+
+	problems := healthProblems(record, history)
+	if len(problems) == 0 {
+		return
+	}
+	alert := Alert{Source: source.TableName(), Name: source.Name(), Problems: problems, Record: record}
+	if err := HealthNotifier.Notify(ctx, alert); err != nil {
+		log.Error("Sending the source broken alert failed ☠️!", logger.ErrorKey, err)
+	}
+}
+
+// healthProblems returns why a crawl looks broken compared to the recent crawls of the source, if it does
+func healthProblems(record CrawlRecord, history []CrawlRecord) []string {
+	switch record.ErrorKind {
+	case "", "zero_results", "parse":
+	default:
+		// The request failures are reported as failed crawls, they don't say anything about the website
+		return nil
+	}
+
+	var successful []CrawlRecord
+	for _, previous := range history {
+		if previous.ErrorKind == "" {
+			successful = append(successful, previous)
+		}
+	}
+	enoughHistory := len(successful) >= MinHealthHistory
+
+	var problems []string
+	if record.ErrorKind == "parse" {
+		problems = append(problems, "the website couldn't be parsed")
+	}
+
+	medianListed := median(successful, func(r CrawlRecord) (float64, bool) { return float64(r.Listed), true })
+	switch {
+	case record.Listed == 0 && (len(successful) == 0 || medianListed > 0):
+		problems = append(problems, "no positions are listed")
+	case enoughHistory && float64(record.Listed) < HealthListedFraction*medianListed:
+		problems = append(problems, fmt.Sprintf("only %d positions are listed, the recent crawls listed %.0f", record.Listed, medianListed))
+	}
+
+	if record.Extracted == 0 {
+		return problems
+	}
+	titleRate := float64(record.EmptyTitles) / float64(record.Extracted)
+	descriptionRate := float64(record.EmptyDescriptions) / float64(record.Extracted)
+	if !enoughHistory {
+		if record.EmptyTitles == record.Extracted {
+			problems = append(problems, "none of the positions has a title")
+		}
+		return problems
+	}
+
+	usualTitleRate := median(successful, func(r CrawlRecord) (float64, bool) {
+		return float64(r.EmptyTitles) / float64(r.Extracted), r.Extracted > 0
+	})
+	if titleRate > usualTitleRate+MaxEmptyRateIncrease {
+		problems = append(problems, fmt.Sprintf("%.0f%% of the positions have no title, usually %.0f%%", 100*titleRate, 100*usualTitleRate))
+	}
+	usualDescriptionRate := median(successful, func(r CrawlRecord) (float64, bool) {
+		return float64(r.EmptyDescriptions) / float64(r.Extracted), r.Extracted > 0
+	})
+	if descriptionRate > usualDescriptionRate+MaxEmptyRateIncrease {
+		problems = append(problems, fmt.Sprintf("%.0f%% of the positions have no description, usually %.0f%%", 100*descriptionRate, 100*usualDescriptionRate))
+	}
+	return problems
+}
+
+// median returns the median of the values of the records, the records for which value returns false are skipped
+func median(records []CrawlRecord, value func(CrawlRecord) (float64, bool)) float64 {
+	var values []float64
+	for _, record := range records {
+		if v, ok := value(record); ok {
+			values = append(values, v)
+		}
+	}
+	if len(values) == 0 {
+		return 0
+	}
+	sort.Float64s(values)
+	middle := len(values) / 2
+	if len(values)%2 == 0 {
+		return (values[middle-1] + values[middle]) / 2
+	}
+	return values[middle]
+}
+
+// LogNotifier logs the alerts at the error level
+type LogNotifier struct{}
+
+func (LogNotifier) Notify(ctx context.Context, alert Alert) error {
+	logger.ForSource(alert.Source).Error("Source broken 🚨!", "problems", strings.Join(alert.Problems, "; "),
+		"listed", alert.Record.Listed, "extracted", alert.Record.Extracted)
+	return nil
+}
+
+// WebhookNotifier posts the alerts as JSON to a URL, the message is in the "text" field
+// so it can be posted to a Slack or Mattermost incoming webhook
+type WebhookNotifier struct {
+	URL string
+	// Client is the HTTP client of the requests, http.DefaultClient if it is nil
+	Client *http.Client
+}
+
+func (n *WebhookNotifier) Notify(ctx context.Context, alert Alert) error {
+	body, err := json.Marshal(map[string]interface{}{
+		"text":     fmt.Sprintf("Source broken 🚨: %s (%s): %s", alert.Name, alert.Source, strings.Join(alert.Problems, "; ")),
+		"source":   alert.Source,
+		"problems": alert.Problems,
+		"record":   alert.Record,
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	client := n.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return &StatusError{URL: n.URL, StatusCode: resp.StatusCode, Err: errors.New(resp.Status)}
+	}
+	return nil
+}
+
+// Notifiers sends the alerts through all its notifiers
+type Notifiers []Notifier
+
+func (notifiers Notifiers) Notify(ctx context.Context, alert Alert) error {
+	var errs []error
+	for _, notifier := range notifiers {
+		if err := notifier.Notify(ctx, alert); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,118 @@
+package tea
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+// records returns n successful crawl records that listed and extracted the positions
+func records(n, listed, emptyTitles, emptyDescriptions int) []CrawlRecord {
+	var history []CrawlRecord
+	for i := 0; i < n; i++ {
+		history = append(history, CrawlRecord{Listed: listed, Extracted: listed, EmptyTitles: emptyTitles, EmptyDescriptions: emptyDescriptions})
+	}
+	return history
+}
+
+func TestHealthProblems(t *testing.T) {
+	failed := CrawlRecord{ErrorKind: "network"}
+	tests := []struct {
+		name    string
+		record  CrawlRecord
+		history []CrawlRecord
+		want    []string // the beginnings of the problems, none when empty
+	}{
+		{"healthy", CrawlRecord{Listed: 20, Extracted: 20}, records(5, 20, 0, 0), nil},
+		{"short history, fewer listings", CrawlRecord{Listed: 2, Extracted: 2}, records(MinHealthHistory-1, 20, 0, 0), nil},
+		{"short history, more empty descriptions", CrawlRecord{Listed: 20, Extracted: 20, EmptyDescriptions: 20}, records(MinHealthHistory-1, 20, 0, 0), nil},
+		{"short history, no listings", CrawlRecord{}, records(MinHealthHistory-1, 20, 0, 0), []string{"no positions are listed"}},
+		{"short history, no titles", CrawlRecord{Listed: 5, Extracted: 5, EmptyTitles: 5}, nil, []string{"none of the positions has a title"}},
+		{"no history, no listings", CrawlRecord{}, nil, []string{"no positions are listed"}},
+		{"no listings", CrawlRecord{}, records(5, 20, 0, 0), []string{"no positions are listed"}},
+		{"no listings as usual", CrawlRecord{}, records(5, 0, 0, 0), nil},
+		{"listings below the fraction", CrawlRecord{Listed: 9, Extracted: 9}, records(5, 20, 0, 0), []string{"only 9 positions are listed"}},
+		{"listings at the fraction", CrawlRecord{Listed: 10, Extracted: 10}, records(5, 20, 0, 0), nil},
+		{"rising empty titles", CrawlRecord{Listed: 20, Extracted: 20, EmptyTitles: 10}, records(5, 20, 1, 0), []string{"50% of the positions have no title"}},
+		{"rising empty descriptions", CrawlRecord{Listed: 20, Extracted: 20, EmptyDescriptions: 12}, records(5, 20, 0, 4), []string{"60% of the positions have no description"}},
+		{"empty descriptions within the increase", CrawlRecord{Listed: 20, Extracted: 20, EmptyDescriptions: 8}, records(5, 20, 0, 4), nil},
+		{"both empty rates rising", CrawlRecord{Listed: 20, Extracted: 20, EmptyTitles: 20, EmptyDescriptions: 20}, records(5, 20, 0, 0),
+			[]string{"100% of the positions have no title", "100% of the positions have no description"}},
+		// The failed crawls would make the median 0 and hide the drop
+		{"failed crawls left out of the median", CrawlRecord{Listed: 9, Extracted: 9},
+			append(records(MinHealthHistory, 20, 0, 0), failed, failed, failed, failed), []string{"only 9 positions are listed, the recent crawls listed 20"}},
+		{"failed crawls aren't enough history", CrawlRecord{Listed: 9, Extracted: 9},
+			append(records(MinHealthHistory-1, 20, 0, 0), failed, failed, failed), nil},
+		{"request failure", CrawlRecord{ErrorKind: "network"}, records(5, 20, 0, 0), nil},
+		{"parse failure", CrawlRecord{Listed: 20, Extracted: 20, ErrorKind: "parse"}, records(5, 20, 0, 0), []string{"the website couldn't be parsed"}},
+	}
+	for _, test := range tests {
+		problems := healthProblems(test.record, test.history)
+		if len(problems) != len(test.want) {
+			t.Errorf("%s: problems %q, want %q", test.name, problems, test.want)
+			continue
+		}
+		for i, want := range test.want {
+			if !strings.HasPrefix(problems[i], want) {
+				t.Errorf("%s: problems %q, want %q", test.name, problems, test.want)
+				break
+			}
+		}
+	}
+}
+
+// healthSource is a source for checkHealth, which only uses its names
+type healthSource struct{}
+
+func (healthSource) Name() string      { return "University of Health" }
+func (healthSource) TableName() string { return "health_edu" }
+func (healthSource) List(ctx context.Context) ([]Listing, error) {
+	return nil, nil
+}
+func (healthSource) Detail(ctx context.Context, listing Listing) (Position, error) {
+	return Position{}, nil
+}
+
+// recordingNotifier keeps the sent alerts
+type recordingNotifier struct {
+	alerts []Alert
+}
+
+func (n *recordingNotifier) Notify(ctx context.Context, alert Alert) error {
+	n.alerts = append(n.alerts, alert)
+	return nil
+}
+
+func TestCheckHealthNotifiesOnlyProblems(t *testing.T) {
+	notifier := &recordingNotifier{}
+	defer func(previous Notifier) { HealthNotifier = previous }(HealthNotifier)
+	HealthNotifier = notifier
+	store := NewMemoryStore()
+	for _, record := range records(MinHealthHistory, 20, 0, 0) {
+		store.SaveCrawlRecord("health_edu", record)
+	}
+
+	checkHealth(context.Background(), store, healthSource{}, CrawlResult{Listed: 20, SaveResult: SaveResult{New: 2, Unchanged: 18}}, nil)
+	if len(notifier.alerts) != 0 {
+		t.Errorf("a healthy crawl sent the alerts %+v", notifier.alerts)
+	}
+
+	checkHealth(context.Background(), store, healthSource{}, CrawlResult{}, nil)
+	if len(notifier.alerts) != 1 || notifier.alerts[0].Source != "health_edu" || notifier.alerts[0].Name != "University of Health" {
+		t.Fatalf("a crawl listing nothing sent the alerts %+v, want one", notifier.alerts)
+	}
+	if problems := notifier.alerts[0].Problems; len(problems) != 1 || problems[0] != "no positions are listed" {
+		t.Errorf("alert problems %q", problems)
+	}
+
+	// A stopped crawl is neither checked nor recorded
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	checkHealth(ctx, store, healthSource{}, CrawlResult{}, ctx.Err())
+	if len(notifier.alerts) != 1 {
+		t.Errorf("a stopped crawl sent an alert")
+	}
+	if history, _ := store.RecentCrawlRecords("health_edu", HealthHistory); len(history) != MinHealthHistory+2 {
+		t.Errorf("%d crawl records, want the stopped crawl left out", len(history))
+	}
+}
@@ -0,0 +1,163 @@
+package tea
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// MemoryStore is a Store that keeps the positions in memory, it is meant for tests and local runs
+type MemoryStore struct {
+	mu      sync.Mutex
+	sources map[string][]Position
+	// closed has the URLs of the closed positions of each source
+	closed map[string]map[string]bool
+	// rejections has the rejected positions of each source by their URL
+	rejections map[string]map[string]Rejection
+	// records has the crawl records of each source, the oldest first
+	records map[string][]CrawlRecord
+}
+
+// NewMemoryStore returns an empty MemoryStore
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{sources: map[string][]Position{}, closed: map[string]map[string]bool{},
+		rejections: map[string]map[string]Rejection{}, records: map[string][]CrawlRecord{}}
+}
+
+// CreateTablesIfNotExist does nothing, there are no tables to create
+func (s *MemoryStore) CreateTablesIfNotExist() error {
+	return nil
+}
+
+// SavePositions saves the positions of a source, a position whose URL is already saved
+// replaces the saved one if its content has changed, and is not closed anymore
+func (s *MemoryStore) SavePositions(source string, positions []Position) (SaveResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var result SaveResult
+	for _, position := range positions {
+		delete(s.closed[source], position.URL)
+		i := s.indexOf(source, position.URL)
+		switch {
+		case i < 0:
+			s.sources[source] = append(s.sources[source], position)
+			result.New++
+		case ContentHash(s.sources[source][i]) != ContentHash(position):
+			s.sources[source][i] = position
+			result.Changed++
+		default:
+			s.sources[source][i].Deadline = position.Deadline
+			s.sources[source][i].Category = position.Category
+			s.sources[source][i].CategoryConfidence = position.CategoryConfidence
+			s.sources[source][i].Fields = position.Fields
+			result.Unchanged++
+		}
+	}
+	return result, nil
+}
+
+// indexOf returns the index of the saved position of the source with the URL, or -1, s.mu must be held
+func (s *MemoryStore) indexOf(source, url string) int {
+	for i, position := range s.sources[source] {
+		if position.URL == url {
+			return i
+		}
+	}
+	return -1
+}
+
+// GetOpenUrls returns a map of the URLs of the positions of a source that are not closed
+func (s *MemoryStore) GetOpenUrls(source string) (map[string]bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	urls := make(map[string]bool, len(s.sources[source]))
+	for _, position := range s.sources[source] {
+		if !s.closed[source][position.URL] {
+			urls[position.URL] = true
+		}
+	}
+	return urls, nil
+}
+
+// ClosePositions marks the positions of a source with the URLs as closed
+func (s *MemoryStore) ClosePositions(source string, urls []string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed[source] == nil {
+		s.closed[source] = map[string]bool{}
+	}
+	for _, url := range urls {
+		if s.indexOf(source, url) >= 0 {
+			s.closed[source][url] = true
+		}
+	}
+	return nil
+}
+
+// Positions returns a copy of the positions saved for a source
+func (s *MemoryStore) Positions(source string) []Position {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]Position(nil), s.sources[source]...)
+}
+
+// SaveRejections saves the rejected positions of a source, replacing the saved ones with the same URL
+func (s *MemoryStore) SaveRejections(source string, rejections []Rejection) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.rejections[source] == nil {
+		s.rejections[source] = map[string]Rejection{}
+	}
+	for _, rejection := range rejections {
+		s.rejections[source][rejection.URL] = rejection
+	}
+	return nil
+}
+
+// Rejections returns the rejected positions saved for a source
+func (s *MemoryStore) Rejections(source string) []Rejection {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rejections := []Rejection{}
+	for _, rejection := range s.rejections[source] {
+		rejections = append(rejections, rejection)
+	}
+	sort.Slice(rejections, func(i, j int) bool { return rejections[i].URL < rejections[j].URL })
+	return rejections
+}
+
+// SaveCrawlRecord saves the record of a crawl of a source
+func (s *MemoryStore) SaveCrawlRecord(source string, record CrawlRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if record.FinishedOn.IsZero() {
+		record.FinishedOn = time.Now().UTC()
+	}
+	s.records[source] = append(s.records[source], record)
+	return nil
+}
+
+// RecentCrawlRecords returns the last n records of the crawls of a source, the most recent first
+func (s *MemoryStore) RecentCrawlRecords(source string, n int) ([]CrawlRecord, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	records := s.records[source]
+	recent := []CrawlRecord{}
+	for i := len(records) - 1; i >= 0 && len(recent) < n; i-- {
+		recent = append(recent, records[i])
+	}
+	return recent, nil
+}
+
+// Close does nothing, the positions are kept until the program exits
+func (s *MemoryStore) Close() error {
+	return nil
+}
@@ -0,0 +1,9 @@
+package metrics
+
+// The metrics checked by the tests of the metrics_test package, which crawl with the tea package
+var (
+	Requests        = requests
+	Retries         = retries
+	TooManyRequests = tooManyRequests
+	CrawlPositions  = positions
+)
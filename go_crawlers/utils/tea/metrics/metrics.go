@@ -0,0 +1,125 @@
+// Package metrics keeps the Prometheus metrics of the crawls: the requests, retries and 429 responses,
+// the positions and the duration of each crawl, and the time of the last successful crawl of each source.
+// They are served by Serve for Prometheus to scrape, or pushed to a Pushgateway by Push after a cron run.
+package metrics
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/prometheus/client_golang/prometheus/push"
+)
+
+// Registry holds the metrics of the crawls, it doesn't have the Go runtime metrics,
+// so the metrics pushed from different hosts only differ by their labels
+var Registry = prometheus.NewRegistry()
+
+var (
+	requests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "fenjan_requests_total",
+		Help: "Requests made to the university websites by source and status code, the status is \"error\" if there was no response.",
+	}, []string{"source", "status"})
+
+	retries = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "fenjan_request_retries_total",
+		Help: "Failed requests that were retried by source.",
+	}, []string{"source"})
+
+	tooManyRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "fenjan_too_many_requests_total",
+		Help: "429 Too Many Requests responses by source.",
+	}, []string{"source"})
+
+	positions = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "fenjan_crawl_positions",
+		Help: "Positions of the last crawl by source and state: listed, new, updated, rejected, failed or closed.",
+	}, []string{"source", "state"})
+
+	duration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "fenjan_crawl_duration_seconds",
+		Help:    "Duration of the crawls by source and result: success or failure.",
+		Buckets: []float64{10, 30, 60, 120, 300, 600, 900, 1200, 1800, 3600},
+	}, []string{"source", "result"})
+
+	lastSuccess = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "fenjan_crawl_last_success_timestamp_seconds",
+		Help: "Unix time of the end of the last successful crawl by source.",
+	}, []string{"source"})
+)
+
+func init() {
+	Registry.MustRegister(requests, retries, tooManyRequests, positions, duration, lastSuccess)
+}
+
+// Positions are the counts of positions of a crawl
+type Positions struct {
+	Listed, New, Updated, Rejected, Failed, Closed int
+}
+
+// ObserveRequest counts a request to the website of a source, a status code of 0 means there was no response
+func ObserveRequest(source string, statusCode int) {
+	status := "error"
+	if statusCode > 0 {
+		status = strconv.Itoa(statusCode)
+	}
+	requests.WithLabelValues(source, status).Inc()
+	if statusCode == http.StatusTooManyRequests {
+		tooManyRequests.WithLabelValues(source).Inc()
+	}
+}
+
+// ObserveRetry counts a failed request of a source that is retried
+func ObserveRetry(source string) {
+	retries.WithLabelValues(source).Inc()
+}
+
+// ObserveCrawl records the positions and the duration of a crawl,
+// and the time of the last success of the source if err is nil
+func ObserveCrawl(source string, counts Positions, elapsed time.Duration, err error) {
+	for state, count := range map[string]int{
+		"listed": counts.Listed, "new": counts.New, "updated": counts.Updated, "rejected": counts.Rejected, "failed": counts.Failed, "closed": counts.Closed,
+	} {
+		positions.WithLabelValues(source, state).Set(float64(count))
+	}
+
+	result := "success"
+	if err != nil {
+		result = "failure"
+	}
+	duration.WithLabelValues(source, result).Observe(elapsed.Seconds())
+	if err == nil {
+		lastSuccess.WithLabelValues(source).SetToCurrentTime()
+	}
+}
+
+// Handler returns the handler of the /metrics endpoint
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{Registry: Registry})
+}
+
+// Serve serves the metrics on addr at /metrics until ctx is done
+func Serve(ctx context.Context, addr string) error {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", Handler())
+	server := &http.Server{Addr: addr, Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+
+	go func() {
+		<-ctx.Done()
+		server.Close()
+	}()
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// Push replaces the metrics of the job on the Pushgateway at url with the current metrics,
+// it is used by the runs that end before Prometheus could scrape them
+func Push(url, job string) error {
+	return push.New(url, job).Gatherer(Registry).Push()
+}
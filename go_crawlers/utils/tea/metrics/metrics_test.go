@@ -0,0 +1,89 @@
+package metrics_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"fenjan.ai-hue.ir/tea"
+	"fenjan.ai-hue.ir/tea/fakeuni"
+	"fenjan.ai-hue.ir/tea/metrics"
+	"github.com/gocolly/colly"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+// fakeSource crawls the list and detail pages of the fake website
+type fakeSource struct {
+	server *fakeuni.Server
+}
+
+func (fakeSource) Name() string      { return "Metrics University" }
+func (fakeSource) TableName() string { return "metrics_edu" }
+
+func (fakeSource) CollectorOptions() []tea.CollectorOption {
+	// No delay between the requests to keep the test fast
+	return []tea.CollectorOption{tea.WithRateLimit(4, 0, 0)}
+}
+
+func (s fakeSource) List(ctx context.Context) (listings []tea.Listing, err error) {
+	c := tea.NewCollector(ctx)
+	c.OnHTML("li.job a", func(e *colly.HTMLElement) {
+		listings = append(listings, tea.Listing{URL: e.Request.AbsoluteURL(e.Attr("href")), Title: e.Text})
+	})
+	err = c.Visit(s.server.ListURL())
+	return listings, err
+}
+
+func (fakeSource) Detail(ctx context.Context, listing tea.Listing) (tea.Position, error) {
+	position := listing.Position()
+	c := tea.NewCollector(ctx)
+	c.OnHTML("div.description p", func(e *colly.HTMLElement) {
+		position.Description = strings.TrimSpace(e.Text)
+	})
+	err := c.Visit(position.URL)
+	return position, err
+}
+
+func TestCrawlMetrics(t *testing.T) {
+	server := fakeuni.New(
+		fakeuni.Position{ID: "1", Title: "Doctoral student in Physics", Description: "Four years."},
+		fakeuni.Position{ID: "2", Title: "Doctoral student in Chemistry", Description: "Four years."},
+		fakeuni.Position{ID: "3", Title: "Doctoral student in Biology", Description: "Four years."},
+	)
+	defer server.Close()
+	server.TooManyRequests("/jobs/2", 1, "0")
+
+	// The counters keep counting across the runs of the test, so only their increase is checked
+	counters := func() []float64 {
+		return []float64{
+			testutil.ToFloat64(metrics.Requests.WithLabelValues("metrics_edu", "200")),
+			testutil.ToFloat64(metrics.Requests.WithLabelValues("metrics_edu", "429")),
+			testutil.ToFloat64(metrics.Retries.WithLabelValues("metrics_edu")),
+			testutil.ToFloat64(metrics.TooManyRequests.WithLabelValues("metrics_edu")),
+		}
+	}
+	before := counters()
+	if _, err := tea.Crawl(context.Background(), tea.NewMemoryStore(), fakeSource{server: server}); err != nil {
+		t.Fatal(err)
+	}
+	after := counters()
+
+	tests := []struct {
+		name      string
+		got, want float64
+	}{
+		// The list page and the 3 detail pages, the throttled one after its retry
+		{"fenjan_requests_total 200", after[0] - before[0], 4},
+		{"fenjan_requests_total 429", after[1] - before[1], 1},
+		{"fenjan_request_retries_total", after[2] - before[2], 1},
+		{"fenjan_too_many_requests_total", after[3] - before[3], 1},
+		{"fenjan_crawl_positions listed", testutil.ToFloat64(metrics.CrawlPositions.WithLabelValues("metrics_edu", "listed")), 3},
+		{"fenjan_crawl_positions new", testutil.ToFloat64(metrics.CrawlPositions.WithLabelValues("metrics_edu", "new")), 3},
+		{"fenjan_crawl_positions failed", testutil.ToFloat64(metrics.CrawlPositions.WithLabelValues("metrics_edu", "failed")), 0},
+	}
+	for _, test := range tests {
+		if test.got != test.want {
+			t.Errorf("%s = %v, want %v", test.name, test.got, test.want)
+		}
+	}
+}
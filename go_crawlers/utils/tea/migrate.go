@@ -0,0 +1,228 @@
+package tea
+
+import (
+	"database/sql"
+	"embed"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Migrations of the database schema, one directory per dialect.
+// A migration is a pair of files named <version>_<name>.up.sql and <version>_<name>.down.sql
+//
+//go:embed migrations
+var migrationFiles embed.FS
+
+// Migration is a versioned change of the database schema
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+// MigrationStatus is a migration and whether it is applied to the database
+type MigrationStatus struct {
+	Migration
+	Applied bool
+	// AppliedOn is the time the migration was applied, empty if it isn't applied
+	AppliedOn string
+}
+
+// Migrations returns the migrations of the database dialect sorted by version
+func (s *SQLStore) Migrations() ([]Migration, error) {
+	dir := path.Join("migrations", s.dialect.name)
+	entries, err := fs.ReadDir(migrationFiles, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := map[int]*Migration{}
+	for _, entry := range entries {
+		base, direction, ok := cutMigrationName(entry.Name())
+		if !ok {
+			return nil, fmt.Errorf("invalid migration file name %q", entry.Name())
+		}
+		versionText, name, _ := strings.Cut(base, "_")
+		version, err := strconv.Atoi(versionText)
+		if err != nil {
+			return nil, fmt.Errorf("invalid migration version in %q: %w", entry.Name(), err)
+		}
+		data, err := fs.ReadFile(migrationFiles, path.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		migration, found := byVersion[version]
+		if !found {
+			migration = &Migration{Version: version, Name: name}
+			byVersion[version] = migration
+		} else if migration.Name != name {
+			return nil, fmt.Errorf("migrations %q and %q have the same version", migration.Name, name)
+		}
+		if direction == "up" {
+			migration.Up = string(data)
+		} else {
+			migration.Down = string(data)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.Up == "" || migration.Down == "" {
+			return nil, fmt.Errorf("migration %d_%s needs both an up and a down file", migration.Version, migration.Name)
+		}
+		migrations = append(migrations, *migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// cutMigrationName splits "0001_create_positions.up.sql" into "0001_create_positions" and "up"
+func cutMigrationName(fileName string) (base, direction string, ok bool) {
+	for _, direction := range []string{"up", "down"} {
+		suffix := "." + direction + ".sql"
+		if strings.HasSuffix(fileName, suffix) {
+			return strings.TrimSuffix(fileName, suffix), direction, true
+		}
+	}
+	return "", "", false
+}
+
+// createSchemaVersionTable creates the table that keeps the applied migrations
+func (s *SQLStore) createSchemaVersionTable() error {
+	_, err := s.db.Exec(`CREATE TABLE IF NOT EXISTS schema_version (
+		version INT PRIMARY KEY,
+		name VARCHAR(255) NOT NULL,
+		applied_on TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+	)`)
+	return err
+}
+
+// MigrationStatus returns all migrations and whether they are applied to the database
+func (s *SQLStore) MigrationStatus() ([]MigrationStatus, error) {
+	migrations, err := s.Migrations()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.createSchemaVersionTable(); err != nil {
+		return nil, err
+	}
+
+	rows, err := s.db.Query("SELECT version, applied_on FROM schema_version")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	appliedOn := map[int]string{}
+	for rows.Next() {
+		var version int
+		var on sql.NullString
+		if err := rows.Scan(&version, &on); err != nil {
+			return nil, err
+		}
+		appliedOn[version] = on.String
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	statuses := make([]MigrationStatus, len(migrations))
+	for i, migration := range migrations {
+		on, applied := appliedOn[migration.Version]
+		statuses[i] = MigrationStatus{Migration: migration, Applied: applied, AppliedOn: on}
+	}
+	return statuses, nil
+}
+
+// MigrateUp applies all migrations that are not applied yet and returns them
+func (s *SQLStore) MigrateUp() ([]Migration, error) {
+	statuses, err := s.MigrationStatus()
+	if err != nil {
+		return nil, err
+	}
+
+	var applied []Migration
+	for _, status := range statuses {
+		if status.Applied {
+			continue
+		}
+		err := s.runMigration(status.Migration.Up,
+			"INSERT INTO schema_version (version, name) VALUES (?, ?)", status.Version, status.Name)
+		if err != nil {
+			return applied, fmt.Errorf("migration %d_%s: %w", status.Version, status.Name, err)
+		}
+		applied = append(applied, status.Migration)
+	}
+	return applied, nil
+}
+
+// MigrateDown reverts the last steps applied migrations and returns them
+func (s *SQLStore) MigrateDown(steps int) ([]Migration, error) {
+	statuses, err := s.MigrationStatus()
+	if err != nil {
+		return nil, err
+	}
+
+	var reverted []Migration
+	for i := len(statuses) - 1; i >= 0 && len(reverted) < steps; i-- {
+		status := statuses[i]
+		if !status.Applied {
+			continue
+		}
+		err := s.runMigration(status.Migration.Down,
+			"DELETE FROM schema_version WHERE version = ?", status.Version)
+		if err != nil {
+			return reverted, fmt.Errorf("migration %d_%s: %w", status.Version, status.Name, err)
+		}
+		reverted = append(reverted, status.Migration)
+	}
+	return reverted, nil
+}
+
+// runMigration executes the statements of a migration and then updates the schema_version table.
+// MySQL commits schema changes right away, so only SQLite can roll back a failed migration: on MySQL
+// the statements before the failed one stay applied while schema_version is unchanged. The error tells
+// which statement failed, the ones before it have to be reverted by hand before migrating again.
+func (s *SQLStore) runMigration(script string, versionQuery string, versionArgs ...any) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	statements := splitStatements(script)
+	for i, statement := range statements {
+		if _, err := tx.Exec(statement); err != nil {
+			return fmt.Errorf("statement %d of %d failed, the ones before it are applied on MySQL: %w", i+1, len(statements), err)
+		}
+	}
+	if _, err := tx.Exec(versionQuery, versionArgs...); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// splitStatements splits a migration script into its statements,
+// lines starting with "--" are comments and a statement ends with ";"
+func splitStatements(script string) []string {
+	var lines []string
+	for _, line := range strings.Split(script, "\n") {
+		if !strings.HasPrefix(strings.TrimSpace(line), "--") {
+			lines = append(lines, line)
+		}
+	}
+
+	var statements []string
+	for _, statement := range strings.Split(strings.Join(lines, "\n"), ";") {
+		if statement = strings.TrimSpace(statement); statement != "" {
+			statements = append(statements, statement)
+		}
+	}
+	return statements
+}
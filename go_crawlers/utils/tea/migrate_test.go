@@ -0,0 +1,117 @@
+package tea_test
+
+import (
+	"strings"
+	"testing"
+
+	"fenjan.ai-hue.ir/tea"
+)
+
+// schema returns the SQL of the tables and indexes of the database, without schema_version and the ones of SQLite
+func schema(t *testing.T, store *tea.SQLStore) string {
+	rows, err := store.DB().Query("SELECT sql FROM sqlite_master WHERE sql IS NOT NULL AND name != 'schema_version' AND name NOT LIKE 'sqlite_%' ORDER BY name")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+	var statements []string
+	for rows.Next() {
+		var statement string
+		if err := rows.Scan(&statement); err != nil {
+			t.Fatal(err)
+		}
+		statements = append(statements, statement)
+	}
+	return strings.Join(statements, ";\n")
+}
+
+// versions returns the versions of the migrations
+func versions(migrations []tea.Migration) []int {
+	var versions []int
+	for _, migration := range migrations {
+		versions = append(versions, migration.Version)
+	}
+	return versions
+}
+
+// appliedVersions returns the versions of the applied migrations, checking they have the time they were applied
+func appliedVersions(t *testing.T, store *tea.SQLStore) []int {
+	statuses, err := store.MigrationStatus()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var applied []int
+	for _, status := range statuses {
+		if status.Applied != (status.AppliedOn != "") {
+			t.Errorf("migration %d is applied %v on %q", status.Version, status.Applied, status.AppliedOn)
+		}
+		if status.Applied {
+			applied = append(applied, status.Version)
+		}
+	}
+	return applied
+}
+
+func TestMigrateRoundTrip(t *testing.T) {
+	store := openSQLiteStore(t)
+	migrations, err := store.Migrations()
+	if err != nil {
+		t.Fatal(err)
+	}
+	all := versions(migrations)
+	if len(all) != 11 || all[0] != 1 || all[10] != 11 {
+		t.Fatalf("migrations %v, want 1 to 11", all)
+	}
+
+	applied, err := store.MigrateUp()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := versions(applied); len(got) != 11 || got[0] != 1 {
+		t.Fatalf("applied %v, want all migrations", got)
+	}
+	if got := appliedVersions(t, store); len(got) != 11 {
+		t.Errorf("status of the applied migrations %v, want all of them", got)
+	}
+	migrated := schema(t, store)
+
+	// All down scripts revert their migration, the last one first
+	reverted, err := store.MigrateDown(11)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := versions(reverted); len(got) != 11 || got[0] != 11 || got[10] != 1 {
+		t.Errorf("reverted %v, want 11 to 1", got)
+	}
+	if got := appliedVersions(t, store); len(got) != 0 {
+		t.Errorf("applied migrations %v after reverting all of them", got)
+	}
+	if left := schema(t, store); left != "" {
+		t.Errorf("the down scripts left the schema\n%s", left)
+	}
+
+	// Applying them again gives the same schema
+	if applied, err = store.MigrateUp(); err != nil || len(applied) != 11 {
+		t.Fatalf("applied %v again with error %v, want all migrations", versions(applied), err)
+	}
+	if again := schema(t, store); again != migrated {
+		t.Errorf("the schema migrated again is\n%s\nwant\n%s", again, migrated)
+	}
+	if applied, err = store.MigrateUp(); err != nil || len(applied) != 0 {
+		t.Errorf("applied %v with error %v, want nothing left to apply", versions(applied), err)
+	}
+
+	// Reverting some steps leaves the migrations before them applied
+	if reverted, err = store.MigrateDown(2); err != nil {
+		t.Fatal(err)
+	}
+	if got := versions(reverted); len(got) != 2 || got[0] != 11 || got[1] != 10 {
+		t.Errorf("reverted %v, want 11 and 10", got)
+	}
+	if got := appliedVersions(t, store); len(got) != 9 || got[8] != 9 {
+		t.Errorf("applied migrations %v, want 1 to 9", got)
+	}
+	if _, err := store.DB().Exec("SELECT category FROM positions"); err == nil {
+		t.Error("the category column of migration 10 is still there")
+	}
+}
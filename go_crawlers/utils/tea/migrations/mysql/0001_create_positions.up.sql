@@ -0,0 +1,13 @@
+-- positions of all universities, the source is the table name of the university
+CREATE TABLE IF NOT EXISTS positions (
+	id INT AUTO_INCREMENT PRIMARY KEY,
+	source VARCHAR(64) NOT NULL,
+	legacy_id INT NULL,
+	title VARCHAR(500) NOT NULL,
+	url VARCHAR(255) NOT NULL,
+	description TEXT NOT NULL,
+	date VARCHAR(255),
+	scraped_on TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+	UNIQUE KEY positions_source_url (source, url),
+	INDEX positions_scraped_on (scraped_on)
+);
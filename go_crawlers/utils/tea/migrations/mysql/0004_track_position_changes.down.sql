@@ -0,0 +1,4 @@
+ALTER TABLE positions
+	DROP COLUMN content_hash,
+	DROP COLUMN last_seen_on,
+	DROP COLUMN updated_on;
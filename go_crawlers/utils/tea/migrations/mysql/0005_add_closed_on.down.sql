@@ -0,0 +1 @@
+ALTER TABLE positions DROP COLUMN closed_on;
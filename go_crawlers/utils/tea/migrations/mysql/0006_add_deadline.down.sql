@@ -0,0 +1,2 @@
+DROP INDEX positions_deadline ON positions;
+ALTER TABLE positions DROP COLUMN deadline;
@@ -0,0 +1,10 @@
+ALTER TABLE positions
+	DROP COLUMN department,
+	DROP COLUMN city,
+	DROP COLUMN country,
+	DROP COLUMN language,
+	DROP COLUMN employment_type,
+	DROP COLUMN salary,
+	DROP COLUMN duration,
+	DROP COLUMN reference,
+	DROP COLUMN contact;
@@ -0,0 +1,11 @@
+-- optional details of the positions, filled when the university website has them
+ALTER TABLE positions
+	ADD COLUMN department VARCHAR(255) NULL,
+	ADD COLUMN city VARCHAR(255) NULL,
+	ADD COLUMN country VARCHAR(255) NULL,
+	ADD COLUMN language VARCHAR(16) NULL,
+	ADD COLUMN employment_type VARCHAR(255) NULL,
+	ADD COLUMN salary VARCHAR(255) NULL,
+	ADD COLUMN duration VARCHAR(255) NULL,
+	ADD COLUMN reference VARCHAR(255) NULL,
+	ADD COLUMN contact VARCHAR(500) NULL;
@@ -0,0 +1,13 @@
+-- one row per crawl of a source, the health check compares a crawl with the recent ones
+CREATE TABLE IF NOT EXISTS crawl_runs (
+	id INT AUTO_INCREMENT PRIMARY KEY,
+	source VARCHAR(64) NOT NULL,
+	listed INT NOT NULL,
+	extracted INT NOT NULL,
+	failed INT NOT NULL,
+	empty_titles INT NOT NULL,
+	empty_descriptions INT NOT NULL,
+	error_kind VARCHAR(32) NULL,
+	finished_on TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+	INDEX crawl_runs_source_finished_on (source, finished_on)
+);
@@ -0,0 +1,14 @@
+-- listed positions rejected by the title rules of their source before their details are visited,
+-- reason is no_target_keyword or forbidden_keyword, and keyword is the forbidden keyword of the title
+CREATE TABLE IF NOT EXISTS rejected_positions (
+	id INT AUTO_INCREMENT PRIMARY KEY,
+	source VARCHAR(64) NOT NULL,
+	url VARCHAR(255) NOT NULL,
+	title VARCHAR(500) NOT NULL,
+	reason VARCHAR(32) NOT NULL,
+	keyword VARCHAR(255) NULL,
+	rejected_on TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+	last_seen_on TIMESTAMP NULL,
+	UNIQUE KEY rejected_positions_source_url (source, url),
+	INDEX rejected_positions_reason (reason)
+);
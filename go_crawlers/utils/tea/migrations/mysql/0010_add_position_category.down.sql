@@ -0,0 +1,4 @@
+DROP INDEX positions_category ON positions;
+ALTER TABLE positions
+	DROP COLUMN category,
+	DROP COLUMN category_confidence;
@@ -0,0 +1,6 @@
+-- category is the kind of the position labeled by the classify package: phd, postdoc, faculty, staff or other,
+-- category_confidence is the probability of the label
+ALTER TABLE positions
+	ADD COLUMN category VARCHAR(16) NULL,
+	ADD COLUMN category_confidence DOUBLE NULL;
+CREATE INDEX positions_category ON positions (category);
@@ -0,0 +1,9 @@
+-- research fields of the positions tagged with the taxonomy package, field is the ID of a field of
+-- taxonomy.yaml, e.g. computer-vision, a position has a row for each of its fields
+CREATE TABLE IF NOT EXISTS position_fields (
+	source VARCHAR(64) NOT NULL,
+	url VARCHAR(255) NOT NULL,
+	field VARCHAR(64) NOT NULL,
+	PRIMARY KEY (source, url, field),
+	INDEX position_fields_field (field)
+);
@@ -0,0 +1,13 @@
+-- positions of all universities, the source is the table name of the university
+CREATE TABLE IF NOT EXISTS positions (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	source VARCHAR(64) NOT NULL,
+	legacy_id INTEGER NULL,
+	title VARCHAR(500) NOT NULL,
+	url VARCHAR(255) NOT NULL,
+	description TEXT NOT NULL,
+	date VARCHAR(255),
+	scraped_on TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+CREATE UNIQUE INDEX IF NOT EXISTS positions_source_url ON positions (source, url);
+CREATE INDEX IF NOT EXISTS positions_scraped_on ON positions (scraped_on);
@@ -0,0 +1,7 @@
+-- customers of the newsletter, managed by utils/customers_database.py
+CREATE TABLE IF NOT EXISTS customers (
+	name VARCHAR(255),
+	email VARCHAR(255) UNIQUE,
+	expiration_date DATETIME,
+	keywords TEXT
+);
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS keep_track_of_sent_emails;
@@ -0,0 +1,7 @@
+-- positions already sent to each customer, managed by utils/keep_track_of_sent_emails.py
+CREATE TABLE IF NOT EXISTS keep_track_of_sent_emails (
+	id VARCHAR(255) UNIQUE,
+	source VARCHAR(255),
+	customer_email VARCHAR(255),
+	position_id INTEGER
+);
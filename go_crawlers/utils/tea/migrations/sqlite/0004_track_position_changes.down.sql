@@ -0,0 +1,3 @@
+ALTER TABLE positions DROP COLUMN content_hash;
+ALTER TABLE positions DROP COLUMN last_seen_on;
+ALTER TABLE positions DROP COLUMN updated_on;
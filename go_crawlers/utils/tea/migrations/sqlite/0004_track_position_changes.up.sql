@@ -0,0 +1,5 @@
+-- content_hash detects edited positions, updated_on is the last time one was edited
+-- and last_seen_on is the last time it was listed on the university website
+ALTER TABLE positions ADD COLUMN content_hash CHAR(64) NULL;
+ALTER TABLE positions ADD COLUMN last_seen_on TIMESTAMP NULL;
+ALTER TABLE positions ADD COLUMN updated_on TIMESTAMP NULL;
@@ -0,0 +1,2 @@
+-- closed_on is the time a position was no longer listed on the university website
+ALTER TABLE positions ADD COLUMN closed_on TIMESTAMP NULL;
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS positions_deadline;
+ALTER TABLE positions DROP COLUMN deadline;
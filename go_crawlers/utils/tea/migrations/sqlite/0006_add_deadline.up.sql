@@ -0,0 +1,3 @@
+-- deadline is the application deadline parsed from date, which keeps the text of the website
+ALTER TABLE positions ADD COLUMN deadline DATETIME NULL;
+CREATE INDEX positions_deadline ON positions (deadline);
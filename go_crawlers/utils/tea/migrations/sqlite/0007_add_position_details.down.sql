@@ -0,0 +1,9 @@
+ALTER TABLE positions DROP COLUMN department;
+ALTER TABLE positions DROP COLUMN city;
+ALTER TABLE positions DROP COLUMN country;
+ALTER TABLE positions DROP COLUMN language;
+ALTER TABLE positions DROP COLUMN employment_type;
+ALTER TABLE positions DROP COLUMN salary;
+ALTER TABLE positions DROP COLUMN duration;
+ALTER TABLE positions DROP COLUMN reference;
+ALTER TABLE positions DROP COLUMN contact;
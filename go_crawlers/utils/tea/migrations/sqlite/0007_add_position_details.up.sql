@@ -0,0 +1,10 @@
+-- optional details of the positions, filled when the university website has them
+ALTER TABLE positions ADD COLUMN department VARCHAR(255) NULL;
+ALTER TABLE positions ADD COLUMN city VARCHAR(255) NULL;
+ALTER TABLE positions ADD COLUMN country VARCHAR(255) NULL;
+ALTER TABLE positions ADD COLUMN language VARCHAR(16) NULL;
+ALTER TABLE positions ADD COLUMN employment_type VARCHAR(255) NULL;
+ALTER TABLE positions ADD COLUMN salary VARCHAR(255) NULL;
+ALTER TABLE positions ADD COLUMN duration VARCHAR(255) NULL;
+ALTER TABLE positions ADD COLUMN reference VARCHAR(255) NULL;
+ALTER TABLE positions ADD COLUMN contact VARCHAR(500) NULL;
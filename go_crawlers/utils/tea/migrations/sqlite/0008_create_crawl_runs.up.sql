@@ -0,0 +1,13 @@
+-- one row per crawl of a source, the health check compares a crawl with the recent ones
+CREATE TABLE IF NOT EXISTS crawl_runs (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	source VARCHAR(64) NOT NULL,
+	listed INTEGER NOT NULL,
+	extracted INTEGER NOT NULL,
+	failed INTEGER NOT NULL,
+	empty_titles INTEGER NOT NULL,
+	empty_descriptions INTEGER NOT NULL,
+	error_kind VARCHAR(32) NULL,
+	finished_on TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS crawl_runs_source_finished_on ON crawl_runs (source, finished_on);
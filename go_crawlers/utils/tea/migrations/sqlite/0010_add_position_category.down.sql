@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS positions_category;
+ALTER TABLE positions DROP COLUMN category;
+ALTER TABLE positions DROP COLUMN category_confidence;
@@ -0,0 +1,5 @@
+-- category is the kind of the position labeled by the classify package: phd, postdoc, faculty, staff or other,
+-- category_confidence is the probability of the label
+ALTER TABLE positions ADD COLUMN category VARCHAR(16) NULL;
+ALTER TABLE positions ADD COLUMN category_confidence REAL NULL;
+CREATE INDEX positions_category ON positions (category);
@@ -0,0 +1,99 @@
+package tea
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+var (
+	registryMu sync.Mutex
+	registry   = map[string]registration{}
+)
+
+// Location is where the positions of a source are and the language they are written in, a field is
+// empty when it differs between the positions, e.g. for a university with campuses in several cities
+type Location struct {
+	City    string
+	Country string
+	// Language is the ISO 639-1 code of the language, e.g. "en"
+	Language string
+}
+
+// registration is a registered source with the location of its positions
+type registration struct {
+	source   Source
+	location Location
+}
+
+// Register adds a source to the registry with the location of its positions, it is meant to be called
+// from the init function of each university package. Crawl sets the location on the positions whose
+// Detail leaves it empty. It panics if the source can't be registered, see TryRegister.
+func Register(source Source, location Location) {
+	if err := TryRegister(source, location); err != nil {
+		panic("tea: " + err.Error())
+	}
+}
+
+// TryRegister adds a source to the registry like Register, it returns an error if the source has no
+// table name or a source with the same table name is already registered
+func TryRegister(source Source, location Location) error {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	tableName := source.TableName()
+	if tableName == "" {
+		return fmt.Errorf("source %q needs a table name", source.Name())
+	}
+	if _, exists := registry[tableName]; exists {
+		return fmt.Errorf("source %q registered twice", tableName)
+	}
+	registry[tableName] = registration{source: source, location: location}
+	return nil
+}
+
+// Sources returns all registered sources sorted by their table name
+func Sources() []Source {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	sources := make([]Source, 0, len(registry))
+	for _, r := range registry {
+		sources = append(sources, r.source)
+	}
+	sort.Slice(sources, func(i, j int) bool {
+		return sources[i].TableName() < sources[j].TableName()
+	})
+	return sources
+}
+
+// LookupSource returns the registered source with the given table name
+func LookupSource(tableName string) (Source, bool) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	r, ok := registry[tableName]
+	return r.source, ok
+}
+
+// LookupLocation returns the location the source with the given table name was registered with
+func LookupLocation(tableName string) (Location, bool) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	r, ok := registry[tableName]
+	return r.location, ok
+}
+
+// fill sets the location on the fields of the position that are empty
+func (l Location) fill(position *Position) {
+	if position.City == "" {
+		position.City = l.City
+	}
+	if position.Country == "" {
+		position.Country = l.Country
+	}
+	if position.Language == "" {
+		position.Language = l.Language
+	}
+}
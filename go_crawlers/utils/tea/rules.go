@@ -0,0 +1,98 @@
+package tea
+
+import "strings"
+
+// The reasons a position is rejected by the title rules of its source
+const (
+	// RejectedNoTargetKeyword is the reason of a title without any of the target keywords
+	RejectedNoTargetKeyword = "no_target_keyword"
+	// RejectedForbiddenKeyword is the reason of a title with a forbidden keyword
+	RejectedForbiddenKeyword = "forbidden_keyword"
+)
+
+// TitleRules select the positions of a university that also advertises postdoc and staff positions
+// by their title, the keywords are searched case-insensitively anywhere in the title
+type TitleRules struct {
+	// Target keywords, the title must have one of them, any title is accepted if there is none
+	Target []string `yaml:"target" json:"target"`
+	// Forbidden keywords, the title must have none of them
+	Forbidden []string `yaml:"forbidden" json:"forbidden"`
+}
+
+// Reject returns the reason the rules reject the title for, and the forbidden keyword it has,
+// the reason is empty if the title is accepted
+func (r TitleRules) Reject(title string) (reason, keyword string) {
+	title = strings.ToLower(title)
+	if len(r.Target) > 0 && !containsAny(title, r.Target) {
+		return RejectedNoTargetKeyword, ""
+	}
+	for _, forbidden := range r.Forbidden {
+		if strings.Contains(title, strings.ToLower(forbidden)) {
+			return RejectedForbiddenKeyword, forbidden
+		}
+	}
+	return "", ""
+}
+
+func containsAny(text string, keywords []string) bool {
+	for _, keyword := range keywords {
+		if strings.Contains(text, strings.ToLower(keyword)) {
+			return true
+		}
+	}
+	return false
+}
+
+// TitleFilter is implemented by the sources whose positions are selected by TitleRules. The rules are
+// applied to the titles of the list page before the details are visited, and to the titles of the
+// detail pages of the listings without one.
+type TitleFilter interface {
+	TitleRules() TitleRules
+}
+
+// Rejection is a listed position rejected by the title rules of its source, it is saved
+// so what was filtered can be audited
+type Rejection struct {
+	URL   string `json:"url"`
+	Title string `json:"title"`
+	// Reason is RejectedNoTargetKeyword or RejectedForbiddenKeyword
+	Reason string `json:"reason"`
+	// Keyword is the forbidden keyword of the title, empty for the other reasons
+	Keyword string `json:"keyword"`
+}
+
+// rejectListings splits the listings with a title into the accepted and the rejected ones,
+// the listings without a title are accepted, their title is checked after getting their details
+func rejectListings(rules TitleRules, listings []Listing) (accepted []Listing, rejections []Rejection) {
+	accepted = []Listing{}
+	for _, listing := range listings {
+		title := strings.TrimSpace(listing.Title)
+		if title != "" {
+			if reason, keyword := rules.Reject(title); reason != "" {
+				rejections = append(rejections, Rejection{URL: listing.URL, Title: title, Reason: reason, Keyword: keyword})
+				continue
+			}
+		}
+		accepted = append(accepted, listing)
+	}
+	return accepted, rejections
+}
+
+// rejectPositions splits the extracted positions into the accepted and the rejected ones, the positions
+// without a title are accepted, as their selectors are probably broken, which the health check reports
+func rejectPositions(rules TitleRules, positions []Position) (accepted []Position, rejections []Rejection) {
+	accepted = []Position{}
+	for _, position := range positions {
+		title := strings.TrimSpace(position.Title)
+		if title == "" {
+			accepted = append(accepted, position)
+			continue
+		}
+		if reason, keyword := rules.Reject(title); reason != "" {
+			rejections = append(rejections, Rejection{URL: position.URL, Title: title, Reason: reason, Keyword: keyword})
+			continue
+		}
+		accepted = append(accepted, position)
+	}
+	return accepted, rejections
+}
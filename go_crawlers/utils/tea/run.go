@@ -0,0 +1,111 @@
+package tea
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"fenjan.ai-hue.ir/logger"
+)
+
+// RunOptions are the limits of crawling many sources with Run
+type RunOptions struct {
+	// Concurrency is the number of sources crawled at the same time
+	Concurrency int
+	// Retries is the number of times a failed crawl of a source is tried again
+	Retries int
+	// RetryWait is the wait before the first retry, it grows with each retry
+	RetryWait time.Duration
+	// Timeout limits each attempt of crawling a source, zero means no limit
+	Timeout time.Duration
+}
+
+// RunReport is the outcome of crawling a source with Run
+type RunReport struct {
+	Source Source
+	// Result counts the positions of the last attempt, the new and changed positions
+	// of the failed attempts are added to it because they were saved too
+	Result CrawlResult
+	// Attempts is the number of times the source was crawled
+	Attempts int
+	// Err is the error of the last attempt, nil if the source was crawled successfully
+	Err error
+	// Duration of all attempts, including the waits between them
+	Duration time.Duration
+}
+
+// Run crawls the sources concurrently, at most options.Concurrency at a time, and tries again
+// the sources whose crawl failed. It returns the reports in the order of the sources.
+// When ctx is done, the running crawls are stopped and the waiting sources are not crawled.
+func Run(ctx context.Context, store Store, sources []Source, options RunOptions) []RunReport {
+	if options.Concurrency < 1 {
+		options.Concurrency = 1
+	}
+
+	reports := make([]RunReport, len(sources))
+	slots := make(chan struct{}, options.Concurrency)
+	var wg sync.WaitGroup
+	for i, source := range sources {
+		reports[i].Source = source
+
+		select {
+		case slots <- struct{}{}:
+		case <-ctx.Done():
+			reports[i].Err = ctx.Err()
+			continue
+		}
+		// The select takes the free slot at random when ctx is done too
+		if err := ctx.Err(); err != nil {
+			<-slots
+			reports[i].Err = err
+			continue
+		}
+
+		wg.Add(1)
+		go func(report *RunReport) {
+			defer wg.Done()
+			defer func() { <-slots }()
+			runSource(ctx, store, report, options)
+		}(&reports[i])
+	}
+	wg.Wait()
+	return reports
+}
+
+// runSource crawls the source of the report until it succeeds or runs out of retries
+func runSource(ctx context.Context, store Store, report *RunReport, options RunOptions) {
+	start := time.Now()
+	defer func() { report.Duration = time.Since(start) }()
+
+	var saved SaveResult
+	for attempt := 0; attempt <= options.Retries; attempt++ {
+		if attempt > 0 {
+			wait := options.RetryWait * time.Duration(attempt)
+			logger.ForSource(report.Source.TableName()).Warn("Crawling failed, trying again 🔁.", logger.AttemptKey, attempt,
+				"retries", options.Retries, "wait", wait, logger.KindKey, ErrorKind(report.Err), logger.ErrorKey, report.Err)
+			if Sleep(ctx, wait) != nil {
+				break
+			}
+		}
+
+		report.Attempts++
+		report.Result, report.Err = crawlWithTimeout(ctx, store, report.Source, options.Timeout)
+		saved.New += report.Result.New
+		saved.Changed += report.Result.Changed
+		// Stop when it succeeded, or when the whole run is stopped
+		if report.Err == nil || ctx.Err() != nil {
+			break
+		}
+	}
+	report.Result.New, report.Result.Changed = saved.New, saved.Changed
+}
+
+// crawlWithTimeout crawls a source, giving up after the timeout
+func crawlWithTimeout(ctx context.Context, store Store, source Source, timeout time.Duration) (CrawlResult, error) {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	return Crawl(ctx, store, source)
+}
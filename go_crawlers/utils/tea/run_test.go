@@ -0,0 +1,158 @@
+package tea_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"fenjan.ai-hue.ir/tea"
+)
+
+// runSource is a source without a website, whose listing fails on its first attempts after listing
+// one position more at each attempt, so the failed attempts save new positions too
+type runSource struct {
+	name string
+	// failures is the number of failing attempts
+	failures int
+	// block makes List wait until ctx is done
+	block bool
+	// running counts the sources being listed at the same time, and most is the highest count
+	running, most *int
+	mu            *sync.Mutex
+	attempts      *[]time.Time
+}
+
+func newRunSource(name string, failures int, running, most *int, mu *sync.Mutex) runSource {
+	return runSource{name: name, failures: failures, running: running, most: most, mu: mu, attempts: &[]time.Time{}}
+}
+
+func (s runSource) Name() string      { return "University " + s.name }
+func (s runSource) TableName() string { return s.name }
+
+func (s runSource) List(ctx context.Context) ([]tea.Listing, error) {
+	s.mu.Lock()
+	*s.attempts = append(*s.attempts, time.Now())
+	attempt := len(*s.attempts)
+	*s.running++
+	if *s.running > *s.most {
+		*s.most = *s.running
+	}
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		*s.running--
+		s.mu.Unlock()
+	}()
+
+	if s.block {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	// Give the other sources the time to run at the same time, if they could
+	time.Sleep(10 * time.Millisecond)
+	var listings []tea.Listing
+	for i := 1; i <= attempt && i <= s.failures+1; i++ {
+		listings = append(listings, tea.Listing{URL: fmt.Sprintf("https://%s.example.com/%d", s.name, i), Title: fmt.Sprintf("PhD student %d", i)})
+	}
+	if attempt <= s.failures {
+		return listings, &tea.NetworkError{URL: "https://" + s.name + ".example.com/jobs?page=2", Err: errors.New("connection reset")}
+	}
+	return listings, nil
+}
+
+func (s runSource) Detail(ctx context.Context, listing tea.Listing) (tea.Position, error) {
+	position := listing.Position()
+	position.Description = "Funded for four years."
+	return position, nil
+}
+
+func TestRunRetriesWithBackoff(t *testing.T) {
+	var running, most int
+	var mu sync.Mutex
+	flaky := newRunSource("flaky_edu", 2, &running, &most, &mu)
+	steady := newRunSource("steady_edu", 0, &running, &most, &mu)
+	options := tea.RunOptions{Concurrency: 1, Retries: 2, RetryWait: 50 * time.Millisecond}
+
+	reports := tea.Run(context.Background(), tea.NewMemoryStore(), []tea.Source{flaky, steady}, options)
+	if reports[0].Source != flaky || reports[1].Source != steady {
+		t.Fatalf("reports of %s and %s, want them in the order of the sources", reports[0].Source.Name(), reports[1].Source.Name())
+	}
+
+	// The new positions of the failed attempts were saved, they are added to the last attempt
+	report := reports[0]
+	if report.Err != nil || report.Attempts != 3 {
+		t.Errorf("flaky source: %d attempts and error %v, want 3 attempts and no error", report.Attempts, report.Err)
+	}
+	if report.Result.New != 3 || report.Result.Unchanged != 2 || report.Result.Changed != 0 {
+		t.Errorf("flaky source: %+v, want 3 new positions in all and 2 unchanged in the last attempt", report.Result.SaveResult)
+	}
+	// The wait grows with each retry
+	attempts := *flaky.attempts
+	for i := 1; i < len(attempts); i++ {
+		if wait := options.RetryWait * time.Duration(i); attempts[i].Sub(attempts[i-1]) < wait {
+			t.Errorf("retry %d after %v, want at least %v", i, attempts[i].Sub(attempts[i-1]), wait)
+		}
+	}
+	if report.Duration < 3*options.RetryWait {
+		t.Errorf("flaky source took %v, want the waits included", report.Duration)
+	}
+
+	if reports[1].Err != nil || reports[1].Attempts != 1 || reports[1].Result.New != 1 {
+		t.Errorf("steady source: %+v", reports[1])
+	}
+	if most != 1 {
+		t.Errorf("%d sources were crawled at the same time, want 1", most)
+	}
+}
+
+func TestRunConcurrency(t *testing.T) {
+	var running, most int
+	var mu sync.Mutex
+	var sources []tea.Source
+	for i := 0; i < 6; i++ {
+		sources = append(sources, newRunSource(fmt.Sprintf("source_%d_edu", i), 0, &running, &most, &mu))
+	}
+
+	for _, concurrency := range []int{1, 3} {
+		most = 0
+		tea.Run(context.Background(), tea.NewMemoryStore(), sources, tea.RunOptions{Concurrency: concurrency})
+		if most > concurrency || most == 0 {
+			t.Errorf("%d sources were crawled at the same time, want at most %d", most, concurrency)
+		}
+	}
+}
+
+func TestRunCanceledSkipsWaitingSources(t *testing.T) {
+	var running, most int
+	var mu sync.Mutex
+	blocking := newRunSource("blocking_edu", 0, &running, &most, &mu)
+	blocking.block = true
+	waiting := newRunSource("waiting_edu", 0, &running, &most, &mu)
+
+	// The run is stopped while the first source is crawled, the second one waits for its slot
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		cancel()
+	}()
+	reports := tea.Run(ctx, tea.NewMemoryStore(), []tea.Source{blocking, waiting}, tea.RunOptions{Concurrency: 1, Retries: 3, RetryWait: time.Millisecond})
+
+	// The stopped crawl isn't tried again
+	if reports[0].Attempts != 1 || !errors.Is(reports[0].Err, context.Canceled) {
+		t.Errorf("stopped source: %d attempts and error %v, want 1 canceled attempt", reports[0].Attempts, reports[0].Err)
+	}
+	if reports[1].Attempts != 0 || !errors.Is(reports[1].Err, context.Canceled) || len(*waiting.attempts) != 0 {
+		t.Errorf("waiting source: %d attempts and error %v, want it skipped", reports[1].Attempts, reports[1].Err)
+	}
+
+	// A run that is stopped before it starts skips all sources
+	reports = tea.Run(ctx, tea.NewMemoryStore(), []tea.Source{waiting, waiting}, tea.RunOptions{Concurrency: 2})
+	for _, report := range reports {
+		if report.Attempts != 0 || !errors.Is(report.Err, context.Canceled) {
+			t.Errorf("source of a stopped run: %d attempts and error %v, want it skipped", report.Attempts, report.Err)
+		}
+	}
+}
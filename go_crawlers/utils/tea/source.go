@@ -0,0 +1,319 @@
+package tea
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"fenjan.ai-hue.ir/logger"
+	"fenjan.ai-hue.ir/tea/classify"
+	"fenjan.ai-hue.ir/tea/deadline"
+	"fenjan.ai-hue.ir/tea/metrics"
+	"fenjan.ai-hue.ir/tea/taxonomy"
+)
+
+// DetailWorkers is the number of positions whose details are extracted at the same time,
+// the requests to each domain are still limited by the rate limit of the collector
+var DetailWorkers = 8
+
+// Now returns the current time, which the deadlines without a year or relative to today are parsed at,
+// the tests replace it to get the same deadlines every day
+var Now = time.Now
+
+// Classifier labels the extracted positions whose source didn't set their category,
+// fenjan-crawl replaces it with a classifier of a model trained offline
+var Classifier = classify.Default()
+
+// Tagger tags the extracted positions whose source didn't set their research fields
+var Tagger = taxonomy.Default()
+
+// MinListedFraction guards against closing positions because of a broken list page:
+// the positions that are no longer listed are closed only when the list has at least
+// this fraction of the open positions of the source
+const MinListedFraction = 0.5
+
+// Listing is a position as it is found on the list page of a university website,
+// only the URL is always set, the other fields are filled when the list page shows them
+type Listing struct {
+	URL         string
+	Title       string
+	Description string
+	Date        string
+	// Deadline is set when the list page has it in a structured form, otherwise it's parsed from Date
+	// for the DeadlineFromDate sources
+	Deadline   time.Time
+	Department string
+	Reference  string
+}
+
+// Position returns a Position with the fields already known from the listing
+func (l Listing) Position() Position {
+	return Position{Title: l.Title, URL: l.URL, Description: l.Description, Date: l.Date, Deadline: l.Deadline,
+		Department: l.Department, Reference: l.Reference}
+}
+
+// Source is a university website with vacant positions, each university package implements it
+// with the parsing specific to its website and registers it with Register
+type Source interface {
+	// Name of the university, e.g. "KTH Royal Institute of Technology"
+	Name() string
+	// TableName identifies the university, e.g. "kth_se", it is the source of its positions in the store,
+	// the name of its old per university table, and the name used to select it on the command line
+	TableName() string
+	// List returns the positions advertised on the university website, when it fails after finding
+	// some of them, e.g. on a later page, it returns them with the error so they are still saved
+	List(ctx context.Context) ([]Listing, error)
+	// Detail visits the page of a listed position and returns the position with all its fields
+	Detail(ctx context.Context, listing Listing) (Position, error)
+}
+
+// CollectorPolicy is implemented by the sources whose website needs a different timeout or rate limit
+// than the default policy of NewCollector
+type CollectorPolicy interface {
+	// CollectorOptions returns the options of the collector shared by all requests of a crawl
+	CollectorOptions() []CollectorOption
+}
+
+// DeadlineFromDate is implemented by the sources whose Date is the application deadline of the positions,
+// their Deadline is parsed from it when the source doesn't set it. The Date of the other sources can be
+// anything, e.g. the publication date, so their Deadline is left unknown.
+type DeadlineFromDate interface {
+	// DateIsDeadline reports whether the Date of the positions is their application deadline
+	DateIsDeadline() bool
+}
+
+// CrawlResult counts the positions of a source handled by Crawl
+type CrawlResult struct {
+	// Listed positions were found on the list page
+	Listed int
+	// SaveResult counts the new, changed and unchanged positions that were saved
+	SaveResult
+	// Rejected positions were listed but rejected by the title rules of the source
+	Rejected int
+	// Failed positions were listed but their details couldn't be extracted
+	Failed int
+	// Closed positions were open but aren't listed anymore
+	Closed int
+	// EmptyTitles and EmptyDescriptions count the extracted positions without a title or a description,
+	// many of them usually mean the selectors of the source don't match its website anymore
+	EmptyTitles       int
+	EmptyDescriptions int
+}
+
+// Crawl lists the positions of a source, gets their details, and saves them to the store,
+// the details of positions saved before are visited again to find the ones that have changed,
+// and the open positions that are no longer listed are closed.
+// The listings rejected by the TitleRules of a TitleFilter source are saved as rejections instead.
+// When ctx is done or listing fails part way, the positions extracted so far are saved and the error
+// is returned without closing any position. A source that lists no positions returns a ZeroResultsError.
+// The errors of the requests are a NetworkError, a StatusError or a ParseError.
+// The metrics of the crawl and of its requests are recorded in the metrics package.
+func Crawl(ctx context.Context, store Store, source Source) (CrawlResult, error) {
+	start := time.Now()
+	result, err := crawl(ctx, store, source)
+	metrics.ObserveCrawl(source.TableName(), metrics.Positions{
+		Listed: result.Listed, New: result.New, Updated: result.Changed, Rejected: result.Rejected, Failed: result.Failed, Closed: result.Closed,
+	}, time.Since(start), err)
+	checkHealth(ctx, store, source, result, err)
+	return result, err
+}
+
+func crawl(ctx context.Context, store Store, source Source) (CrawlResult, error) {
+	var result CrawlResult
+
+	// Log the records of the crawl, including the ones of its collectors, with the source field
+	log := logger.ForSource(source.TableName())
+	ctx = logger.NewContext(ctx, log)
+	ctx = context.WithValue(ctx, sourceNameKey{}, source.TableName())
+
+	// Creating the positions table if not exist
+	log.Info("Creating the tables in the 'fenjan' database if not exists 👾.")
+	if err := store.CreateTablesIfNotExist(); err != nil {
+		return result, err
+	}
+
+	// Share one collector, and so its rate limits, between all requests of the crawl
+	var options []CollectorOption
+	if policy, ok := source.(CollectorPolicy); ok {
+		options = policy.CollectorOptions()
+	}
+	ctx = withSharedCollector(ctx, NewCollector(ctx, options...))
+
+	// Getting the URL of vacant positions on the university site
+	log.Info(fmt.Sprintf("Searching the %s for the Ph.D. vacancies 🦉.", source.Name()))
+	listings, listErr := source.List(ctx)
+	if listErr != nil && len(listings) == 0 {
+		return result, listErr
+	}
+	if listErr != nil {
+		log.Warn("Listing failed part way, saving the positions found so far 🩹.", "listed", len(listings), logger.ErrorKey, listErr)
+	}
+	result.Listed = len(listings)
+	log.Info("Found the open positions 🐝", "listed", len(listings))
+
+	// Skip the details of the positions whose title on the list page is rejected
+	accepted := listings
+	var rejections []Rejection
+	filter, filtered := source.(TitleFilter)
+	if filtered {
+		accepted, rejections = rejectListings(filter.TitleRules(), listings)
+	}
+
+	// Extract details of the positions, stop when ctx is done but keep the positions already extracted
+	positions, failed := extractDetails(ctx, source, accepted)
+	result.Failed = failed
+	log.Info("Extracted details of the open positions 🤓.", "extracted", len(positions), "failed", failed)
+
+	// Save the rejected positions, including the ones whose title was only on their detail page
+	if filtered {
+		var rejected []Rejection
+		positions, rejected = rejectPositions(filter.TitleRules(), positions)
+		rejections = append(rejections, rejected...)
+		result.Rejected = len(rejections)
+	}
+	if len(rejections) > 0 {
+		if err := store.SaveRejections(source.TableName(), rejections); err != nil {
+			return result, err
+		}
+		log.Info("Rejected positions by their title 🙅.", "rejected", len(rejections))
+	}
+
+	// Many positions without a title or a description mean the selectors are broken
+	for _, position := range positions {
+		if strings.TrimSpace(position.Title) == "" {
+			result.EmptyTitles++
+		}
+		if strings.TrimSpace(position.Description) == "" {
+			result.EmptyDescriptions++
+		}
+	}
+
+	// Saving the positions to the database
+	log.Info("Saving positions to the database 🚀...")
+	var err error
+	result.SaveResult, err = store.SavePositions(source.TableName(), positions)
+	if err != nil {
+		return result, err
+	}
+	log.Info("Saved positions 📊.", "new", result.New, "changed", result.Changed, "unchanged", result.Unchanged)
+
+	// The listing wasn't fully crawled, so the positions that are missing can't be closed
+	if err := ctx.Err(); err != nil {
+		log.Warn("Crawling was stopped before getting the details of all positions 🛑.", logger.ErrorKey, err)
+		return result, err
+	}
+	if listErr != nil {
+		return result, listErr
+	}
+	if len(listings) == 0 {
+		return result, &ZeroResultsError{Source: source.Name()}
+	}
+
+	// Closing the positions that are no longer listed
+	result.Closed, err = closeUnlistedPositions(log, store, source, listings)
+	if err != nil {
+		return result, err
+	}
+
+	log.Info("Finished 🫡!")
+	return result, nil
+}
+
+// extractDetails gets the details of the listings with DetailWorkers workers, the positions whose
+// details can't be extracted are skipped and counted, they will be tried again in the next run
+func extractDetails(ctx context.Context, source Source, listings []Listing) (positions []Position, failed int) {
+	details := make([]*Position, len(listings))
+	var failures int32
+	location, _ := LookupLocation(source.TableName())
+	dates, datesAreDeadlines := source.(DeadlineFromDate)
+	datesAreDeadlines = datesAreDeadlines && dates.DateIsDeadline()
+
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < DetailWorkers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				if ctx.Err() != nil {
+					continue
+				}
+				// A position extracted just as ctx is done is kept, the ones that failed because of it are not
+				// counted as failures
+				position, err := source.Detail(ctx, listings[i])
+				if err != nil && ctx.Err() != nil {
+					continue
+				}
+				if err != nil {
+					logger.Failure(logger.FromContext(ctx), listings[i].URL, ErrorKind(err), err)
+					atomic.AddInt32(&failures, 1)
+					continue
+				}
+				location.fill(&position)
+				if position.Deadline.IsZero() && datesAreDeadlines {
+					position.Deadline, _ = deadline.Parse(position.Date, Now().UTC())
+				}
+				if position.Category == "" && Classifier != nil {
+					result := Classifier.Classify(position.Title, position.Description)
+					position.Category, position.CategoryConfidence = string(result.Label), result.Confidence
+				}
+				if position.Fields == nil && Tagger != nil {
+					position.Fields = Tagger.Tag(position.Title, position.Description)
+				}
+				details[i] = &position
+			}
+		}()
+	}
+
+send:
+	for i := range listings {
+		select {
+		case indexes <- i:
+		case <-ctx.Done():
+			break send
+		}
+	}
+	close(indexes)
+	wg.Wait()
+
+	// Keep the order of the listings
+	positions = []Position{}
+	for _, position := range details {
+		if position != nil {
+			positions = append(positions, *position)
+		}
+	}
+	return positions, int(failures)
+}
+
+// closeUnlistedPositions closes the open positions of a source that are not in the listings,
+// unless the listings are suspiciously few compared to the open positions
+func closeUnlistedPositions(log *slog.Logger, store Store, source Source, listings []Listing) (int, error) {
+	openUrls, err := store.GetOpenUrls(source.TableName())
+	if err != nil {
+		return 0, err
+	}
+
+	if len(listings) == 0 || float64(len(listings)) < MinListedFraction*float64(len(openUrls)) {
+		log.Warn("Only a few positions are listed compared to the open ones, not closing any position 🤨.", "listed", len(listings), "open", len(openUrls))
+		return 0, nil
+	}
+
+	for _, listing := range listings {
+		delete(openUrls, listing.URL)
+	}
+	unlisted := make([]string, 0, len(openUrls))
+	for url := range openUrls {
+		unlisted = append(unlisted, url)
+	}
+	if len(unlisted) == 0 {
+		return 0, nil
+	}
+
+	log.Info("Closing the positions that are no longer listed 🪦.", "closed", len(unlisted))
+	return len(unlisted), store.ClosePositions(source.TableName(), unlisted)
+}
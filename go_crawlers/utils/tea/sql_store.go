@@ -0,0 +1,492 @@
+package tea
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	_ "github.com/go-sql-driver/mysql"
+	_ "modernc.org/sqlite"
+)
+
+// PositionsTable is the table that keeps the positions of all universities
+const PositionsTable = "positions"
+
+// dialect holds the SQL that differs between the supported databases
+type dialect struct {
+	// name is the directory of the dialect's migrations
+	name string
+	// insertIgnore starts an insert statement that skips the rows breaking a unique constraint
+	insertIgnore string
+	// now is the expression of the current time
+	now string
+	// tableExists counts the tables with the name given as the only parameter
+	tableExists string
+}
+
+var mysqlDialect = dialect{
+	name:         "mysql",
+	insertIgnore: "INSERT IGNORE INTO",
+	now:          "NOW()",
+	tableExists:  "SELECT COUNT(*) FROM information_schema.tables WHERE table_schema = DATABASE() AND table_name = ?",
+}
+
+var sqliteDialect = dialect{
+	name:         "sqlite",
+	insertIgnore: "INSERT OR IGNORE INTO",
+	now:          "CURRENT_TIMESTAMP",
+	tableExists:  "SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = ?",
+}
+
+// SQLStore is a Store backed by a MySQL or SQLite database
+type SQLStore struct {
+	db      *sql.DB
+	dialect dialect
+	// migrateMu keeps the sources crawled at the same time from migrating the database together
+	migrateMu sync.Mutex
+}
+
+// NewMySQLStore returns a Store that uses a MySQL database
+func NewMySQLStore(db *sql.DB) *SQLStore {
+	return &SQLStore{db: db, dialect: mysqlDialect}
+}
+
+// NewSQLiteStore returns a Store that uses a SQLite database, e.g. to run the crawlers locally
+func NewSQLiteStore(db *sql.DB) *SQLStore {
+	return &SQLStore{db: db, dialect: sqliteDialect}
+}
+
+// DB returns the underlying database
+func (s *SQLStore) DB() *sql.DB {
+	return s.db
+}
+
+// CreateTablesIfNotExist function applies the migrations that are not applied yet to the database
+func (s *SQLStore) CreateTablesIfNotExist() error {
+	s.migrateMu.Lock()
+	defer s.migrateMu.Unlock()
+	_, err := s.MigrateUp()
+	return err
+}
+
+// SavePositions function saves the scraped positions of a source to the database,
+// a position whose URL is already saved for the source is updated if its content hash has changed,
+// and its deadline, category and last_seen_on are set and closed_on is cleared in any case, its fields
+// are only written again when they differ from the saved ones
+func (s *SQLStore) SavePositions(source string, positions []Position) (SaveResult, error) {
+	var result SaveResult
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return result, err
+	}
+	defer tx.Rollback()
+
+	// The saved positions are read in the transaction they are written in, not before it
+	savedHashes, err := contentHashes(tx, source)
+	if err != nil {
+		return result, err
+	}
+	savedFields, err := positionFields(tx, source)
+	if err != nil {
+		return result, err
+	}
+
+	// Prepare the SQL statements
+	insert, err := tx.Prepare(fmt.Sprintf(`INSERT INTO positions (source, title, url, description, date, deadline, %[2]s, category, category_confidence, content_hash, scraped_on, last_seen_on)
+		VALUES (?, ?, ?, ?, ?, ?, %[3]s, ?, ?, ?, %[1]s, %[1]s)`,
+		s.dialect.now, strings.Join(detailColumns, ", "), strings.TrimSuffix(strings.Repeat("?, ", len(detailColumns)), ", ")))
+	if err != nil {
+		return result, err
+	}
+	defer insert.Close()
+
+	update, err := tx.Prepare(fmt.Sprintf(`UPDATE positions SET title = ?, description = ?, date = ?, deadline = ?, %[2]s, category = ?, category_confidence = ?, content_hash = ?, updated_on = %[1]s, last_seen_on = %[1]s, closed_on = NULL
+		WHERE source = ? AND url = ?`, s.dialect.now, strings.Join(detailColumns, " = ?, ")+" = ?"))
+	if err != nil {
+		return result, err
+	}
+	defer update.Close()
+
+	seen, err := tx.Prepare(fmt.Sprintf("UPDATE positions SET deadline = ?, category = ?, category_confidence = ?, last_seen_on = %s, closed_on = NULL WHERE source = ? AND url = ?", s.dialect.now))
+	if err != nil {
+		return result, err
+	}
+	defer seen.Close()
+
+	fields, err := s.prepareFields(tx)
+	if err != nil {
+		return result, err
+	}
+	defer fields.Close()
+
+	// Loop through each position and execute the SQL statement that it needs
+	for _, position := range positions {
+		hash := ContentHash(position)
+		deadline := deadlineValue(position.Deadline)
+		category, confidence := categoryValues(position)
+		savedHash, saved := savedHashes[position.URL]
+		switch {
+		case !saved:
+			args := append([]interface{}{source, position.Title, position.URL, position.Description, position.Date, deadline}, detailValues(position)...)
+			_, err = insert.Exec(append(args, category, confidence, hash)...)
+			result.New++
+		case savedHash != hash:
+			args := append([]interface{}{position.Title, position.Description, position.Date, deadline}, detailValues(position)...)
+			_, err = update.Exec(append(args, category, confidence, hash, source, position.URL)...)
+			result.Changed++
+		default:
+			_, err = seen.Exec(deadline, category, confidence, source, position.URL)
+			result.Unchanged++
+		}
+		if err == nil && !sameFields(savedFields[position.URL], position.Fields) {
+			err = fields.replace(source, position.URL, position.Fields)
+		}
+		if err != nil {
+			return SaveResult{}, err
+		}
+		savedHashes[position.URL] = hash
+		savedFields[position.URL] = position.Fields
+	}
+
+	if err := tx.Commit(); err != nil {
+		return SaveResult{}, err
+	}
+	return result, nil
+}
+
+// detailColumns are the columns of the optional details of a position
+var detailColumns = []string{"department", "city", "country", "language", "employment_type", "salary", "duration", "reference", "contact"}
+
+// detailValues returns the optional details of a position as they are saved in the database, NULL if they are empty
+func detailValues(position Position) []interface{} {
+	var values []interface{}
+	for _, detail := range position.details() {
+		if detail == "" {
+			values = append(values, nil)
+		} else {
+			values = append(values, detail)
+		}
+	}
+	return values
+}
+
+// deadlineValue returns the deadline as it is saved in the database, NULL if it's unknown
+func deadlineValue(deadline time.Time) interface{} {
+	if deadline.IsZero() {
+		return nil
+	}
+	return deadline.UTC().Format("2006-01-02 15:04:05")
+}
+
+// categoryValues returns the category and its confidence as they are saved in the database,
+// NULL if the position isn't labeled
+func categoryValues(position Position) (interface{}, interface{}) {
+	if position.Category == "" {
+		return nil, nil
+	}
+	return position.Category, position.CategoryConfidence
+}
+
+// fieldStatements replace the rows of the research fields of a position in position_fields
+type fieldStatements struct {
+	delete, insert *sql.Stmt
+}
+
+func (s *SQLStore) prepareFields(tx *sql.Tx) (*fieldStatements, error) {
+	deleteFields, err := tx.Prepare("DELETE FROM position_fields WHERE source = ? AND url = ?")
+	if err != nil {
+		return nil, err
+	}
+	insertField, err := tx.Prepare("INSERT INTO position_fields (source, url, field) VALUES (?, ?, ?)")
+	if err != nil {
+		deleteFields.Close()
+		return nil, err
+	}
+	return &fieldStatements{delete: deleteFields, insert: insertField}, nil
+}
+
+// replace replaces the saved fields of the position with the fields
+func (f *fieldStatements) replace(source, url string, fields []string) error {
+	if _, err := f.delete.Exec(source, url); err != nil {
+		return err
+	}
+	for _, field := range fields {
+		if _, err := f.insert.Exec(source, url, field); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (f *fieldStatements) Close() {
+	f.delete.Close()
+	f.insert.Close()
+}
+
+// TagPositions tags all saved positions again with the research fields returned by tag, e.g. after
+// the taxonomy has changed, and returns the number of tagged positions
+func (s *SQLStore) TagPositions(tag func(title, description string) []string) (int, error) {
+	type saved struct {
+		source, url, title, description string
+	}
+	rows, err := s.db.Query("SELECT source, url, title, description FROM positions")
+	if err != nil {
+		return 0, err
+	}
+	var positions []saved
+	for rows.Next() {
+		var position saved
+		if err := rows.Scan(&position.source, &position.url, &position.title, &position.description); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		positions = append(positions, position)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	// The rows are read before writing, SQLite has only one connection
+	tx, err := s.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+	fields, err := s.prepareFields(tx)
+	if err != nil {
+		return 0, err
+	}
+	defer fields.Close()
+	for _, position := range positions {
+		if err := fields.replace(position.source, position.url, tag(position.title, position.description)); err != nil {
+			return 0, err
+		}
+	}
+	return len(positions), tx.Commit()
+}
+
+// positionFields returns the saved research fields of the positions of a source by their URL
+func positionFields(tx *sql.Tx, source string) (map[string][]string, error) {
+	rows, err := tx.Query("SELECT url, field FROM position_fields WHERE source = ?", source)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	fields := make(map[string][]string)
+	for rows.Next() {
+		var url, field string
+		if err := rows.Scan(&url, &field); err != nil {
+			return nil, err
+		}
+		fields[url] = append(fields[url], field)
+	}
+	return fields, rows.Err()
+}
+
+// sameFields reports whether the two lists have the same fields in any order
+func sameFields(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	count := make(map[string]int, len(a))
+	for _, field := range a {
+		count[field]++
+	}
+	for _, field := range b {
+		if count[field] == 0 {
+			return false
+		}
+		count[field]--
+	}
+	return true
+}
+
+// contentHashes returns the content hash of the saved positions of a source by their URL,
+// the hash of a position saved before the hashes were stored is computed from its columns
+func contentHashes(tx *sql.Tx, source string) (map[string]string, error) {
+	rows, err := tx.Query("SELECT url, content_hash, title, description, date FROM positions WHERE source = ?", source)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	hashes := make(map[string]string)
+	for rows.Next() {
+		var url string
+		var hash, date sql.NullString
+		var position Position
+		if err := rows.Scan(&url, &hash, &position.Title, &position.Description, &date); err != nil {
+			return nil, err
+		}
+		if hash.Valid {
+			hashes[url] = hash.String
+		} else {
+			position.Date = date.String
+			hashes[url] = ContentHash(position)
+		}
+	}
+	return hashes, rows.Err()
+}
+
+// GetOpenUrls function return a map of the urls of the positions of a source that are not closed
+func (s *SQLStore) GetOpenUrls(source string) (map[string]bool, error) {
+	rows, err := s.db.Query("SELECT url FROM positions WHERE source = ? AND closed_on IS NULL", source)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	// Create a map to store the URLs
+	urls := make(map[string]bool)
+	for rows.Next() {
+		var url string
+		if err := rows.Scan(&url); err != nil {
+			return nil, err
+		}
+		urls[url] = true
+	}
+	return urls, rows.Err()
+}
+
+// ClosePositions function sets closed_on of the positions of a source with the urls
+func (s *SQLStore) ClosePositions(source string, urls []string) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(fmt.Sprintf("UPDATE positions SET closed_on = %s WHERE source = ? AND url = ? AND closed_on IS NULL", s.dialect.now))
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, url := range urls {
+		if _, err := stmt.Exec(source, url); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// SaveRejections function saves the rejected positions of a source to the rejected_positions table,
+// a position rejected before keeps its rejected_on time and gets the title, reason and last_seen_on of now
+func (s *SQLStore) SaveRejections(source string, rejections []Rejection) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	insert, err := tx.Prepare(fmt.Sprintf(`%s rejected_positions (source, url, title, reason, keyword, rejected_on, last_seen_on)
+		VALUES (?, ?, ?, ?, ?, %[2]s, %[2]s)`, s.dialect.insertIgnore, s.dialect.now))
+	if err != nil {
+		return err
+	}
+	defer insert.Close()
+
+	update, err := tx.Prepare(fmt.Sprintf(`UPDATE rejected_positions SET title = ?, reason = ?, keyword = ?, last_seen_on = %s
+		WHERE source = ? AND url = ?`, s.dialect.now))
+	if err != nil {
+		return err
+	}
+	defer update.Close()
+
+	for _, rejection := range rejections {
+		var keyword interface{}
+		if rejection.Keyword != "" {
+			keyword = rejection.Keyword
+		}
+		if _, err := insert.Exec(source, rejection.URL, rejection.Title, rejection.Reason, keyword); err != nil {
+			return err
+		}
+		if _, err := update.Exec(rejection.Title, rejection.Reason, keyword, source, rejection.URL); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// SaveCrawlRecord function saves the record of a crawl of a source to the crawl_runs table
+func (s *SQLStore) SaveCrawlRecord(source string, record CrawlRecord) error {
+	var errorKind interface{}
+	if record.ErrorKind != "" {
+		errorKind = record.ErrorKind
+	}
+	finishedOn := record.FinishedOn
+	if finishedOn.IsZero() {
+		finishedOn = time.Now()
+	}
+	_, err := s.db.Exec(`INSERT INTO crawl_runs (source, listed, extracted, failed, empty_titles, empty_descriptions, error_kind, finished_on)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)`, source, record.Listed, record.Extracted, record.Failed,
+		record.EmptyTitles, record.EmptyDescriptions, errorKind, finishedOn.UTC().Format("2006-01-02 15:04:05"))
+	return err
+}
+
+// RecentCrawlRecords function returns the last n records of the crawls of a source, the most recent first
+func (s *SQLStore) RecentCrawlRecords(source string, n int) ([]CrawlRecord, error) {
+	rows, err := s.db.Query(`SELECT listed, extracted, failed, empty_titles, empty_descriptions, error_kind, finished_on
+		FROM crawl_runs WHERE source = ? ORDER BY finished_on DESC, id DESC LIMIT ?`, source, n)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	records := []CrawlRecord{}
+	for rows.Next() {
+		var record CrawlRecord
+		var errorKind, finishedOn sql.NullString
+		err := rows.Scan(&record.Listed, &record.Extracted, &record.Failed, &record.EmptyTitles, &record.EmptyDescriptions,
+			&errorKind, &finishedOn)
+		if err != nil {
+			return nil, err
+		}
+		record.ErrorKind = errorKind.String
+		record.FinishedOn = parseTimestamp(finishedOn.String)
+		records = append(records, record)
+	}
+	return records, rows.Err()
+}
+
+// parseTimestamp parses a TIMESTAMP column read as text, which is formatted differently by MySQL and SQLite
+func parseTimestamp(value string) time.Time {
+	for _, layout := range []string{"2006-01-02 15:04:05", time.RFC3339, "2006-01-02T15:04:05Z"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// CopyLegacyTable copies the positions of the old per university table into the positions table,
+// the table name is used as the source and the old id is kept in legacy_id.
+// Positions that are already copied are skipped, so it is safe to run it more than once.
+// It returns the number of copied positions, or -1 if the table doesn't exist.
+func (s *SQLStore) CopyLegacyTable(tableName string) (int64, error) {
+	var count int
+	if err := s.db.QueryRow(s.dialect.tableExists, tableName).Scan(&count); err != nil {
+		return 0, err
+	}
+	if count == 0 {
+		return -1, nil
+	}
+
+	query := fmt.Sprintf(`%s positions (source, legacy_id, title, url, description, date, scraped_on)
+		SELECT ?, id, title, url, description, date, scraped_on FROM %s ORDER BY id`, s.dialect.insertIgnore, tableName)
+	result, err := s.db.Exec(query, tableName)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// Close closes the database
+func (s *SQLStore) Close() error {
+	return s.db.Close()
+}
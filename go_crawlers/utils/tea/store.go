@@ -0,0 +1,119 @@
+package tea
+
+import (
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/joho/godotenv"
+)
+
+// Store keeps the scraped positions of all universities, each position is saved with the
+// source it was scraped from, which is the table name of the university source
+type Store interface {
+	// CreateTablesIfNotExist creates the tables of the store if they don't already exist
+	CreateTablesIfNotExist() error
+	// SavePositions saves the scraped positions of a source, new positions are added,
+	// and saved positions whose content has changed are updated
+	SavePositions(source string, positions []Position) (SaveResult, error)
+	// GetOpenUrls returns a map of the URLs of the positions of a source that are not closed
+	GetOpenUrls(source string) (map[string]bool, error)
+	// ClosePositions marks the positions of a source with the URLs as closed
+	ClosePositions(source string, urls []string) error
+	// SaveRejections saves the listed positions of a source rejected by its title rules
+	SaveRejections(source string, rejections []Rejection) error
+	// SaveCrawlRecord saves the record of a crawl of a source
+	SaveCrawlRecord(source string, record CrawlRecord) error
+	// RecentCrawlRecords returns the last n records of the crawls of a source, the most recent first
+	RecentCrawlRecords(source string, n int) ([]CrawlRecord, error)
+	// Close releases the connection to the database
+	Close() error
+}
+
+// SaveResult counts the positions saved by SavePositions
+type SaveResult struct {
+	// New positions weren't saved before
+	New int
+	// Changed positions were saved before with a different title, description or date
+	Changed int
+	// Unchanged positions were saved before with the same content
+	Unchanged int
+}
+
+// CrawlRecord is what the health check keeps of a crawl of a source
+type CrawlRecord struct {
+	Listed    int `json:"listed"`
+	Extracted int `json:"extracted"`
+	Failed    int `json:"failed"`
+	// EmptyTitles and EmptyDescriptions count the extracted positions without a title or a description
+	EmptyTitles       int `json:"empty_titles"`
+	EmptyDescriptions int `json:"empty_descriptions"`
+	// ErrorKind is the ErrorKind of the error of the crawl, empty if it succeeded
+	ErrorKind  string    `json:"error_kind"`
+	FinishedOn time.Time `json:"finished_on"`
+}
+
+// ContentHash returns the hash of the fields of a position that a university may edit,
+// it is used to find the saved positions that have changed
+func ContentHash(position Position) string {
+	fields := []string{position.Title, position.Description, position.Date}
+	// The location and language are left out because they are the same for all positions of a source.
+	// The other details are hashed only when there are some, so the positions of the sources that
+	// don't parse them keep the hash they had before the details were added.
+	details := []string{position.Department, position.EmploymentType, position.Salary, position.Duration,
+		position.Reference, position.Contact}
+	for _, detail := range details {
+		if detail != "" {
+			fields = append(fields, details...)
+			break
+		}
+	}
+
+	hash := sha256.New()
+	for _, field := range fields {
+		hash.Write([]byte(field))
+		hash.Write([]byte{0})
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// OpenStore opens the store selected by the DB_DRIVER environment variable:
+// "mysql" (the default) uses the DB_* connection details, "sqlite" uses the file in DB_PATH,
+// and "memory" keeps the positions in memory until the program exits
+func OpenStore() (Store, error) {
+
+	// Load environment variables from .env file if there is one
+	if _, err := os.Stat(ProjectRootPath + "/.env"); err == nil {
+		if err := godotenv.Load(ProjectRootPath + "/.env"); err != nil {
+			return nil, fmt.Errorf("error loading .env file: %w", err)
+		}
+	}
+
+	switch driver := os.Getenv("DB_DRIVER"); driver {
+	case "", "mysql":
+		db, err := sql.Open("mysql", GetDbConnectionString())
+		if err != nil {
+			return nil, err
+		}
+		return NewMySQLStore(db), nil
+	case "sqlite":
+		path := os.Getenv("DB_PATH")
+		if path == "" {
+			path = "fenjan.db"
+		}
+		db, err := sql.Open("sqlite", path)
+		if err != nil {
+			return nil, err
+		}
+		// SQLite allows one writer at a time, so the sources crawled at the same time share one connection
+		db.SetMaxOpenConns(1)
+		return NewSQLiteStore(db), nil
+	case "memory":
+		return NewMemoryStore(), nil
+	default:
+		return nil, fmt.Errorf("unknown DB_DRIVER %q, use mysql, sqlite or memory", driver)
+	}
+}
@@ -0,0 +1,119 @@
+package tea_test
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"fenjan.ai-hue.ir/tea"
+)
+
+func TestContentHash(t *testing.T) {
+	position := tea.Position{Title: "Doctoral student in Physics", Description: "Four years.", Date: "2030-06-30"}
+	hash := tea.ContentHash(position)
+
+	// The location and language of the source don't change the hash of the positions saved before them
+	located := position
+	located.City, located.Country, located.Language = "Stockholm", "Sweden", "en"
+	if tea.ContentHash(located) != hash {
+		t.Error("the location and language changed the hash")
+	}
+
+	located.Salary = "According to the agreement"
+	if tea.ContentHash(located) == hash {
+		t.Error("the salary didn't change the hash")
+	}
+}
+
+func TestSavePositionsRewritesOnlyChangedFields(t *testing.T) {
+	store := openSQLiteStore(t)
+	if err := store.CreateTablesIfNotExist(); err != nil {
+		t.Fatal(err)
+	}
+	// A trigger counts the deleted fields, the fields are deleted and inserted again when they are rewritten
+	_, err := store.DB().Exec(`CREATE TABLE deleted_fields (url VARCHAR(255));
+		CREATE TRIGGER count_deleted_fields AFTER DELETE ON position_fields BEGIN INSERT INTO deleted_fields VALUES (OLD.url); END`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	deleted := func() int {
+		var count int
+		if err := store.DB().QueryRow("SELECT COUNT(*) FROM deleted_fields").Scan(&count); err != nil {
+			t.Fatal(err)
+		}
+		return count
+	}
+	position := tea.Position{Title: "Doctoral student in Computer Vision", URL: "https://example.com/1", Fields: []string{"computer-science", "computer-vision"}}
+	if _, err := store.SavePositions("kth_se", []tea.Position{position}); err != nil {
+		t.Fatal(err)
+	}
+
+	// The same fields in another order, with a changed content
+	position.Description = "Five years."
+	position.Fields = []string{"computer-vision", "computer-science"}
+	if _, err := store.SavePositions("kth_se", []tea.Position{position}); err != nil {
+		t.Fatal(err)
+	}
+	if n := deleted(); n != 0 {
+		t.Errorf("the unchanged fields were written again, %d were deleted", n)
+	}
+
+	position.Fields = []string{"computer-science"}
+	if _, err := store.SavePositions("kth_se", []tea.Position{position}); err != nil {
+		t.Fatal(err)
+	}
+	var count int
+	if err := store.DB().QueryRow("SELECT COUNT(*) FROM position_fields").Scan(&count); err != nil {
+		t.Fatal(err)
+	}
+	if count != 1 || deleted() != 2 {
+		t.Errorf("%d saved fields and %d deleted, want the changed fields written again", count, deleted())
+	}
+}
+
+func TestCopyLegacyTable(t *testing.T) {
+	store := openSQLiteStore(t)
+	if err := store.CreateTablesIfNotExist(); err != nil {
+		t.Fatal(err)
+	}
+	if copied, err := store.CopyLegacyTable("kth_se"); err != nil || copied != -1 {
+		t.Fatalf("copied %d positions of a missing table with error %v, want -1", copied, err)
+	}
+
+	// The old per university table of the Python scripts
+	_, err := store.DB().Exec(`CREATE TABLE kth_se (id INTEGER PRIMARY KEY AUTOINCREMENT, title VARCHAR(500), url VARCHAR(255),
+			description TEXT, date VARCHAR(255), scraped_on TIMESTAMP DEFAULT CURRENT_TIMESTAMP);
+		INSERT INTO kth_se (id, title, url, description, date) VALUES
+			(5, 'Doctoral student in Physics', 'https://kth.se/5', 'Four years.', '2030-06-30'),
+			(9, 'Doctoral student in Chemistry', 'https://kth.se/9', 'Five years.', NULL)`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if copied, err := store.CopyLegacyTable("kth_se"); err != nil || copied != 2 {
+		t.Fatalf("copied %d positions with error %v, want 2", copied, err)
+	}
+
+	rows, err := store.DB().Query("SELECT source, legacy_id, url, title FROM positions ORDER BY legacy_id")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var copied []string
+	for rows.Next() {
+		var source, url, title string
+		var legacyID int
+		if err := rows.Scan(&source, &legacyID, &url, &title); err != nil {
+			t.Fatal(err)
+		}
+		copied = append(copied, fmt.Sprintf("%s %d %s %s", source, legacyID, url, title))
+	}
+	rows.Close()
+	want := []string{"kth_se 5 https://kth.se/5 Doctoral student in Physics", "kth_se 9 https://kth.se/9 Doctoral student in Chemistry"}
+	if !reflect.DeepEqual(copied, want) {
+		t.Errorf("copied positions %q, want %q", copied, want)
+	}
+
+	// The copied positions are skipped
+	if copied, err := store.CopyLegacyTable("kth_se"); err != nil || copied != 0 {
+		t.Errorf("copied %d positions again with error %v, want 0", copied, err)
+	}
+}
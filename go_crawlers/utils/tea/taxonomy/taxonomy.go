@@ -0,0 +1,287 @@
+// Package taxonomy tags the positions with the research fields of a controlled vocabulary, e.g.
+// "machine-learning" or "climate", so the matching and the analytics can use stable tags instead of
+// the words the universities happen to write.
+//
+// The fields are grouped under the ERC panels and listed with their synonyms in English, German, Swedish,
+// Finnish and Dutch in taxonomy.yaml, which is embedded as the default taxonomy.
+package taxonomy
+
+import (
+	_ "embed"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"sync"
+	"unicode"
+
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed taxonomy.yaml
+var taxonomyData string
+
+// Field is a research field of the taxonomy
+type Field struct {
+	// ID is the tag of the field, e.g. "computer-vision", it never changes
+	ID   string `yaml:"id" json:"id"`
+	Name string `yaml:"name" json:"name"`
+	// ERC is the ERC panel of the field, e.g. "PE6"
+	ERC string `yaml:"erc" json:"erc"`
+	// Synonyms are the other words of the field, the acronyms in upper case only match in upper case
+	Synonyms []string `yaml:"synonyms" json:"synonyms"`
+	Children []*Field `yaml:"children" json:"children,omitempty"`
+	// Parent is the ID of the field the field is listed under, empty for the top fields
+	Parent string `yaml:"-" json:"parent,omitempty"`
+}
+
+// The scores of the occurrences of a field, a position is tagged with the fields of at least MinScore,
+// so a field in the title is enough, while the description has to mention it twice
+const (
+	TitleScore       = 2
+	DescriptionScore = 1
+	MinScore         = 2
+)
+
+// Taxonomy is a tree of research fields that tags texts with them
+type Taxonomy struct {
+	// fields are all fields in the order of the file, each parent before its children
+	fields []*Field
+	byID   map[string]*Field
+	// patterns are the phrases of the fields by their first word in lower case
+	patterns map[string][]pattern
+	// terms are the normalized names, synonyms and IDs of the fields for Lookup
+	terms map[string]*Field
+}
+
+// pattern is a name or synonym of a field split into words
+type pattern struct {
+	words []string
+	// caseSensitive patterns are acronyms, their words are compared as they are written
+	caseSensitive bool
+	field         *Field
+}
+
+var idPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// Load reads a taxonomy in the format of taxonomy.yaml
+func Load(r io.Reader) (*Taxonomy, error) {
+	var roots []*Field
+	if err := yaml.NewDecoder(r).Decode(&roots); err != nil {
+		return nil, fmt.Errorf("invalid taxonomy: %w", err)
+	}
+
+	t := &Taxonomy{byID: map[string]*Field{}, patterns: map[string][]pattern{}, terms: map[string]*Field{}}
+	var add func(fields []*Field, parent string) error
+	add = func(fields []*Field, parent string) error {
+		for _, field := range fields {
+			if !idPattern.MatchString(field.ID) {
+				return fmt.Errorf("invalid taxonomy: the id %q of %q isn't lower case words joined by dashes", field.ID, field.Name)
+			}
+			if _, ok := t.byID[field.ID]; ok {
+				return fmt.Errorf("invalid taxonomy: the id %q is used twice", field.ID)
+			}
+			field.Parent = parent
+			t.byID[field.ID] = field
+			t.fields = append(t.fields, field)
+			if err := t.addTerms(field); err != nil {
+				return err
+			}
+			if err := add(field.Children, field.ID); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if err := add(roots, ""); err != nil {
+		return nil, err
+	}
+	if len(t.fields) == 0 {
+		return nil, fmt.Errorf("invalid taxonomy: it has no fields")
+	}
+	return t, nil
+}
+
+// addTerms adds the patterns of the name and synonyms of a field, and their terms for Lookup
+func (t *Taxonomy) addTerms(field *Field) error {
+	if other, ok := t.terms[normalize(field.ID)]; ok && other != field {
+		return fmt.Errorf("invalid taxonomy: %q of %s is also a term of %s", field.ID, field.ID, other.ID)
+	}
+	t.terms[normalize(field.ID)] = field
+
+	for _, phrase := range append([]string{field.Name}, field.Synonyms...) {
+		words := words(phrase)
+		if len(words) == 0 {
+			continue
+		}
+		term := normalize(phrase)
+		if other, ok := t.terms[term]; ok && other != field {
+			return fmt.Errorf("invalid taxonomy: %q of %s is also a term of %s", phrase, field.ID, other.ID)
+		}
+		t.terms[term] = field
+
+		caseSensitive := isAcronym(phrase)
+		if !caseSensitive {
+			for i := range words {
+				words[i] = strings.ToLower(words[i])
+			}
+		}
+		t.addPattern(pattern{words: words, caseSensitive: caseSensitive, field: field})
+		// The words written together match too, e.g. "#computervision"
+		if len(words) > 1 && !caseSensitive {
+			t.addPattern(pattern{words: []string{strings.Join(words, "")}, field: field})
+		}
+	}
+	return nil
+}
+
+func (t *Taxonomy) addPattern(p pattern) {
+	first := strings.ToLower(p.words[0])
+	for _, existing := range t.patterns[first] {
+		if existing.field == p.field && strings.Join(existing.words, " ") == strings.Join(p.words, " ") {
+			return
+		}
+	}
+	t.patterns[first] = append(t.patterns[first], p)
+}
+
+var (
+	defaultOnce     sync.Once
+	defaultTaxonomy *Taxonomy
+)
+
+// Default returns the taxonomy of taxonomy.yaml
+func Default() *Taxonomy {
+	defaultOnce.Do(func() {
+		taxonomy, err := Load(strings.NewReader(taxonomyData))
+		if err != nil {
+			panic("taxonomy: invalid taxonomy.yaml: " + err.Error())
+		}
+		defaultTaxonomy = taxonomy
+	})
+	return defaultTaxonomy
+}
+
+// Fields returns all fields, each parent before its children
+func (t *Taxonomy) Fields() []Field {
+	fields := make([]Field, 0, len(t.fields))
+	for _, field := range t.fields {
+		fields = append(fields, *field)
+	}
+	return fields
+}
+
+// Field returns the field of the ID, false if there is no such field
+func (t *Taxonomy) Field(id string) (Field, bool) {
+	field, ok := t.byID[id]
+	if !ok {
+		return Field{}, false
+	}
+	return *field, true
+}
+
+// Lookup returns the field that a keyword of a customer names, ignoring the case, spaces, dashes and hashes,
+// e.g. "#AI", "computer vision" and "ComputerVision" are fields, false if the keyword isn't a term of a field
+func (t *Taxonomy) Lookup(keyword string) (Field, bool) {
+	field, ok := t.terms[normalize(keyword)]
+	if !ok {
+		return Field{}, false
+	}
+	return *field, true
+}
+
+// Tag returns the IDs of the fields of a position with the title and description in the order of the taxonomy,
+// a field is tagged when its occurrences score at least MinScore, and then the fields above it are tagged too
+func (t *Taxonomy) Tag(title, description string) []string {
+	scores := map[*Field]int{}
+	for _, field := range t.match(title) {
+		scores[field] += TitleScore
+	}
+	for _, field := range t.match(description) {
+		scores[field] += DescriptionScore
+	}
+
+	tagged := map[string]bool{}
+	for field, score := range scores {
+		if score < MinScore {
+			continue
+		}
+		for id := field.ID; id != "" && !tagged[id]; id = t.byID[id].Parent {
+			tagged[id] = true
+		}
+	}
+
+	var ids []string
+	for _, field := range t.fields {
+		if tagged[field.ID] {
+			ids = append(ids, field.ID)
+		}
+	}
+	return ids
+}
+
+// match returns the field of each occurrence of a name or synonym in the text, the occurrences are whole words,
+// and only the longest one that starts at a word counts, so "computational linguistics" isn't also "linguistics"
+func (t *Taxonomy) match(text string) []*Field {
+	words := words(text)
+	lower := make([]string, len(words))
+	for i, word := range words {
+		lower[i] = strings.ToLower(word)
+	}
+
+	var fields []*Field
+	for i := 0; i < len(words); {
+		var longest *pattern
+		for j, p := range t.patterns[lower[i]] {
+			if (longest == nil || len(p.words) > len(longest.words)) && p.matches(words[i:], lower[i:]) {
+				longest = &t.patterns[lower[i]][j]
+			}
+		}
+		if longest == nil {
+			i++
+			continue
+		}
+		fields = append(fields, longest.field)
+		i += len(longest.words)
+	}
+	return fields
+}
+
+// matches reports whether the words of the text start with the words of the pattern
+func (p pattern) matches(words, lower []string) bool {
+	if len(words) < len(p.words) {
+		return false
+	}
+	for i, word := range p.words {
+		if p.caseSensitive && words[i] != word || !p.caseSensitive && lower[i] != word {
+			return false
+		}
+	}
+	return true
+}
+
+// words splits the text into its words of letters and digits, dashes and hashes separate words too
+func words(text string) []string {
+	return strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// normalize returns the words of the text in lower case written together
+func normalize(text string) string {
+	return strings.ToLower(strings.Join(words(text), ""))
+}
+
+// isAcronym reports whether the letters of the phrase are all upper case, e.g. "AI" or "HPC"
+func isAcronym(phrase string) bool {
+	letters := 0
+	for _, r := range phrase {
+		if unicode.IsLetter(r) {
+			if !unicode.IsUpper(r) {
+				return false
+			}
+			letters++
+		}
+	}
+	return letters > 0
+}
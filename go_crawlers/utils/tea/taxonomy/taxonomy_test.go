@@ -0,0 +1,65 @@
+package taxonomy_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"fenjan.ai-hue.ir/tea/taxonomy"
+)
+
+func TestTag(t *testing.T) {
+	tests := []struct {
+		title, description string
+		want               []string
+	}{
+		{"Doctoral Researcher in Computer Vision", "", []string{"computer-science", "computer-vision"}},
+		{"Doktorand (m/w/d) Maschinelles Lernen für die Robotik", "",
+			[]string{"computer-science", "machine-learning", "robotics"}},
+		{"Väitöskirjatutkija, tekoäly", "", []string{"computer-science", "artificial-intelligence"}},
+		{"PhD position in AI for climate change", "", []string{"computer-science", "artificial-intelligence", "earth-sciences", "climate"}},
+		// "ai" isn't the acronym, and "maintain" doesn't contain it as a word
+		{"PhD student, ai-driven design", "You will maintain the lab equipment.", nil},
+		// Only the longest phrase counts
+		{"Doctoral student in Computational Linguistics", "", []string{"computer-science", "natural-language-processing"}},
+		// One mention in the description isn't enough, two are
+		{"Doctoral researcher", "The university is known for its physics.", nil},
+		{"Doctoral researcher", "The project combines astronomy with cosmology.", []string{"physics", "astrophysics"}},
+		{"PhD in #DeepLearning", "", []string{"computer-science", "machine-learning"}},
+	}
+	for _, test := range tests {
+		if got := taxonomy.Default().Tag(test.title, test.description); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("Tag(%q, %q) = %v, want %v", test.title, test.description, got, test.want)
+		}
+	}
+}
+
+func TestLookup(t *testing.T) {
+	for keyword, want := range map[string]string{
+		"#AI":                    "artificial-intelligence",
+		"computer vision":        "computer-vision",
+		"ComputerVision":         "computer-vision",
+		"machine-learning":       "machine-learning",
+		"Künstliche Intelligenz": "artificial-intelligence",
+	} {
+		field, ok := taxonomy.Default().Lookup(keyword)
+		if !ok || field.ID != want {
+			t.Errorf("Lookup(%q) = %q, %v, want %q", keyword, field.ID, ok, want)
+		}
+	}
+	if field, ok := taxonomy.Default().Lookup("underwater basket weaving"); ok {
+		t.Errorf("Lookup of an unknown keyword = %q", field.ID)
+	}
+}
+
+func TestLoadRejectsDuplicates(t *testing.T) {
+	for name, data := range map[string]string{
+		"id":      "- id: physics\n  name: Physics\n- id: physics\n  name: Physik\n",
+		"synonym": "- id: physics\n  name: Physics\n- id: astronomy\n  name: Astronomy\n  synonyms: [physics]\n",
+		"format":  "- id: Computer Science\n  name: Computer science\n",
+	} {
+		if _, err := taxonomy.Load(strings.NewReader(data)); err == nil {
+			t.Errorf("Load accepted a taxonomy with an invalid %s", name)
+		}
+	}
+}
@@ -2,15 +2,12 @@
 package tea
 
 import (
-	"database/sql"
-	"fmt"
 	"log"
 	"os"
 	"path/filepath"
 	"runtime"
+	"time"
 
-	_ "github.com/go-sql-driver/mysql"
-	"github.com/gocolly/colly"
 	"github.com/joho/godotenv"
 )
 
@@ -19,6 +16,31 @@ type Position struct {
 	URL         string `json:"url"`
 	Description string `json:"description"`
 	Date        string `json:"date"`
+	// Deadline is the application deadline, parsed from Date for the DeadlineFromDate sources, zero if it's unknown
+	Deadline time.Time `json:"deadline"`
+
+	// Optional details, empty when the university website doesn't have them
+	Department     string `json:"department"`
+	City           string `json:"city"`
+	Country        string `json:"country"`
+	Language       string `json:"language"` // ISO 639-1 code of the language of the posting, e.g. "en"
+	EmploymentType string `json:"employment_type"`
+	Salary         string `json:"salary"`
+	Duration       string `json:"duration"`
+	Reference      string `json:"reference"` // reference number of the position
+	Contact        string `json:"contact"`   // contact person of the position
+
+	// Category is the kind of the position labeled by Classifier, e.g. "phd" or "postdoc", and
+	// CategoryConfidence the probability of the label, they are not part of the content of the position
+	Category           string  `json:"category"`
+	CategoryConfidence float64 `json:"category_confidence"`
+	// Fields are the IDs of the research fields of the position tagged by Tagger, e.g. "computer-vision"
+	Fields []string `json:"fields"`
+}
+
+// details returns the optional details of the position in the order of detailColumns
+func (p Position) details() []string {
+	return []string{p.Department, p.City, p.Country, p.Language, p.EmploymentType, p.Salary, p.Duration, p.Reference, p.Contact}
 }
 
 var (
@@ -47,68 +69,6 @@ func GetDbConnectionString() string {
 	return username + ":" + password + "@tcp(" + host + ":" + port + ")/" + dbname
 }
 
-// CreateTableIfNotExists function creates the __tableName__ table in the database if it doesn't already exist
-func CreateTableIfNotExists(db *sql.DB, tableName string) {
-	// SQL statement to create the table
-	query := fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
-		id INT AUTO_INCREMENT PRIMARY KEY,
-		title VARCHAR(500) NOT NULL,
-		url VARCHAR(255) NOT NULL,
-		description TEXT NOT NULL,
-        date VARCHAR(255),
-		scraped_on TIMESTAMP DEFAULT CURRENT_TIMESTAMP
-	)`, tableName)
-
-	// Execute the SQL statement
-	_, err := db.Exec(query)
-	if err != nil {
-		log.Fatal(err)
-	}
-}
-
-// SavePositionsToDB function saves the scraped positions to a MySQL database
-func SavePositionsToDB(db *sql.DB, positions []Position, tableName string) {
-	// Prepare the SQL statement
-	query := fmt.Sprintf("INSERT INTO %s (title, url, description, date, scraped_on) VALUES (?, ?, ?, ?, NOW())", tableName)
-
-	stmt, err := db.Prepare(query)
-	if err != nil {
-		log.Fatal(err)
-	}
-	defer stmt.Close()
-
-	// Loop through each position and execute the SQL statement
-	for _, position := range positions {
-		_, err := stmt.Exec(position.Title, position.URL, position.Description, position.Date)
-		if err != nil {
-			log.Fatal(err)
-		}
-	}
-}
-
-// GetUrlsFromDB function return a map of all urls in the "positions" table
-func GetUrlsFromDB(db *sql.DB, tableName string) map[string]bool {
-	// SELECT statement to retrieve URLs from the table
-	query := fmt.Sprintf("SELECT url FROM %s", tableName)
-
-	rows, err := db.Query(query)
-	if err != nil {
-		log.Fatal(err)
-	}
-	defer rows.Close()
-
-	// Create a map to store the URLs
-	urls := make(map[string]bool)
-	for rows.Next() {
-		var url string
-		if err := rows.Scan(&url); err != nil {
-			log.Fatal(err)
-		}
-		urls[url] = true
-	}
-	return urls
-}
-
 // https://play.golang.org/p/Qg_uv_inCek
 // contains checks if a string is present in a slice
 func Contains(s []string, str string) bool {
@@ -120,17 +80,3 @@ func Contains(s []string, str string) bool {
 
 	return false
 }
-
-// https://github.com/gocolly/colly/issues/657
-func RetryRequest(r *colly.Response, maxRetries int) int {
-	retriesLeft := maxRetries
-	if x, ok := r.Ctx.GetAny("retriesLeft").(int); ok {
-		retriesLeft = x
-	}
-	if retriesLeft > 0 {
-		r.Ctx.Put("retriesLeft", retriesLeft-1)
-		log.Println("Retrying 🧌!")
-		r.Request.Retry()
-	}
-	return retriesLeft
-}
@@ -0,0 +1,139 @@
+package uva_university_of_amsterdam
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"fenjan.ai-hue.ir/logger"
+	"fenjan.ai-hue.ir/tea"
+	"github.com/gocolly/colly"
+)
+
+// Set the university name, the database table name for this university, and the url of the search page
+var uniName string = "UvA University of Amsterdam"
+var tableName string = "uva_nl"
+var searchUrl string = "https://vacatures.uva.nl/UvA/search/?locale=en_GB"
+
+// Get Position and Listing types from tea helper package
+type Position = tea.Position
+type Listing = tea.Listing
+
+// source implements tea.Source for the university website
+type source struct{}
+
+func (source) Name() string         { return uniName }
+func (source) TableName() string    { return tableName }
+func (source) DateIsDeadline() bool { return true }
+
+// The website is slow, so the requests have a longer timeout
+func (source) CollectorOptions() []tea.CollectorOption {
+	return []tea.CollectorOption{tea.WithTimeout(5 * time.Minute)}
+}
+
+// Find total number of active position current;y advertised on UvA University of Amsterdam
+func findNumActivePositions(ctx context.Context) (int, error) {
+	c := tea.NewCollector(ctx,
+		tea.WithCollyOptions(colly.AllowedDomains("vacatures.uva.nl")),
+	)
+
+	var numPositions int
+	c.OnHTML("span#tile-search-results-label", func(e *colly.HTMLElement) {
+		re := regexp.MustCompile(`\d+`)
+		results := re.FindAllString(e.Text, -1)
+		if len(results) > 0 {
+			numPositions, _ = strconv.Atoi(results[len(results)-1])
+		}
+	})
+
+	err := c.Visit(searchUrl)
+	if err == nil && numPositions == 0 {
+		err = &tea.ParseError{URL: searchUrl, Err: errors.New("the total number of open positions is not found or is equal to 0")}
+	}
+
+	return numPositions, err
+}
+
+// Get url pf positions advertised, the pages of the search results are visited concurrently
+func getPositionsURL(ctx context.Context, numPositions int) ([]string, error) {
+	var positionsURL []string
+	c := tea.NewCollector(ctx)
+
+	c.OnHTML("a[href]", func(e *colly.HTMLElement) {
+		href := "https://vacatures.uva.nl" + e.Attr("href")
+
+		if strings.Contains(strings.ToLower(e.Text), "phd") && !tea.Contains(positionsURL, href) {
+			positionsURL = append(positionsURL, href)
+		}
+	})
+
+	var urls []string
+	for startRow := 0; startRow < numPositions; startRow += 10 {
+		urls = append(urls, fmt.Sprintf("https://vacatures.uva.nl/UvA/tile-search-results/?q=phd&startrow=%d", startRow))
+	}
+	err := c.VisitAll(urls...)
+
+	return positionsURL, err
+}
+
+// Get the positions advertised on the website
+func (source) List(ctx context.Context) (listings []Listing, err error) {
+	log := logger.FromContext(ctx)
+	log.Info("Finding the total number of open positions advertised on the university website 🦎...")
+	numPositions, err := findNumActivePositions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	log.Info("Currently, there are open positions advertised on the website.", "advertised", numPositions)
+
+	positionsURL, err := getPositionsURL(ctx, numPositions)
+	for _, url := range positionsURL {
+		listings = append(listings, Listing{URL: url})
+	}
+	return listings, err
+}
+
+// Extract details of position
+func (source) Detail(ctx context.Context, listing Listing) (Position, error) {
+	position := listing.Position()
+	c := tea.NewCollector(ctx)
+
+	c.OnHTML("h1", func(e *colly.HTMLElement) {
+		position.Title = strings.TrimSpace(e.Text)
+	})
+
+	// The fields of the position are in spans with the id of the careersite property
+	c.OnHTML("span[data-careersite-propertyid]", func(e *colly.HTMLElement) {
+		value := strings.TrimSpace(e.Text)
+		switch e.Attr("data-careersite-propertyid") {
+		case "customfield1":
+			position.Department = value
+		case "customfield3":
+			position.Date = value
+		case "customfield4":
+			position.Salary = value
+		case "customfield5":
+			position.Duration = value
+		case "customfield6":
+			position.EmploymentType = value
+		case "jobnumber":
+			position.Reference = value
+		}
+	})
+	c.OnHTML("span.jobdescription", func(e *colly.HTMLElement) {
+		position.Description = strings.TrimSpace(e.Text)
+	})
+
+	err := c.Visit(position.URL)
+
+	return position, err
+}
+
+func init() {
+	// The positions are written in Dutch or English
+	tea.Register(source{}, tea.Location{City: "Amsterdam", Country: "Netherlands"})
+}
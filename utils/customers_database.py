@@ -53,7 +53,8 @@ class CustomerDatabase:
     def create_customers_table(self, table_name):
         """
         This function creates a table for customer data if it doesn't already exist.
-
+        The schema is managed by the migrations of `fenjan-db migrate`, keep it the same as
+        go_crawlers/utils/tea/migrations/mysql/0002_create_customers.up.sql
         """
         # Create a cursor object to execute SQL commands
         cursor = self.connection.cursor()
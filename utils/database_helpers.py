@@ -15,6 +15,18 @@ class Position:
     description: str
     date: str
     scraped_on: datetime
+    source: str
+    legacy_id: int
+
+    def tracking_key(self):
+        """
+        Returns the table name and id used to keep track of the sent emails.
+        Positions copied from the old per university tables keep their old table name and id,
+        so the emails sent before are not sent again.
+        """
+        if self.legacy_id is not None:
+            return self.source, self.legacy_id
+        return "positions", self.id
 
 
 class PositionsDatabase:
@@ -51,21 +63,27 @@ class PositionsDatabase:
         )
         return self.connection
 
-    def get_positions(self, table_name):
+    def get_positions(self, source):
         """
-        This function connects to the MySQL database and retrieves the values from the 'positions' table.
+        This function connects to the MySQL database and retrieves the open positions of a source,
+        e.g. 'kth_se', from the 'positions' table. Positions that are no longer listed on the
+        university website are closed and not returned.
         It returns a list of Position objects, where each object represents a row of the table.
         """
         cursor = self.connection.cursor()
 
         # SELECT statement to retrieve the values from the positions table
-        query = f"SELECT * FROM {table_name}"
-        cursor.execute(query)
+        query = "SELECT id, title, url, description, date, scraped_on, source, legacy_id FROM positions WHERE source = %s AND closed_on IS NULL"
+        cursor.execute(query, (source,))
 
         # Fetch the rows and create a list of Position objects
         positions = []
-        for (id, title, url, descriptions, date, scraped_on) in cursor:
-            positions.append(Position(id, title, url, descriptions, date, scraped_on))
+        for (id, title, url, descriptions, date, scraped_on, source, legacy_id) in cursor:
+            positions.append(
+                Position(
+                    id, title, url, descriptions, date, scraped_on, source, legacy_id
+                )
+            )
 
         # Close the cursor and connection
         cursor.close()
@@ -51,7 +51,8 @@ class TrackingEmailsDatabase:
     def create_emails_tracking_table(self, table_name):
         """
         This function creates a table for customer data if it doesn't already exist.
-
+        The schema is managed by the migrations of `fenjan-db migrate`, keep it the same as
+        go_crawlers/utils/tea/migrations/mysql/0003_create_keep_track_of_sent_emails.up.sql
         """
         # Create a cursor object to execute SQL commands
         cursor = self.connection.cursor()
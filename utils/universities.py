@@ -1,3 +1,5 @@
+# The Go crawlers have the same target and forbidden keywords in the TitleRules of their sources, and reject
+# the positions that break them before visiting their details; keep both in sync while this script is used.
 class University:
     def __init__(self, name, db_name, target_keywords=[], forbidden_keywords=[]):
         self.name = name